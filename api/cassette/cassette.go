@@ -0,0 +1,330 @@
+// Package cassette records the HTTP traffic of a session to a file and plays
+// it back later without a network, so a session with odd API payloads can be
+// reproduced exactly. Credentials never reach the file: authentication
+// headers are dropped and credential query parameters blanked before an
+// exchange is written.
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// redactedHeaders never leave the process: the API key and request signature,
+// plus the usual credentials carried by other services
+var redactedHeaders = []string{
+	"x-api-key",
+	"x-signature",
+	"x-timestamp",
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// redactedParams are query parameters that carry credentials, such as the
+// API keys of the market data and news services. Names match in any case.
+var redactedParams = []string{
+	"apiKey",
+	"api_key",
+	"auth_token",
+	"token",
+	"key",
+}
+
+// Interaction is one recorded request and the response it received
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded part of an outgoing request
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is the recorded part of a response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// redact returns a copy of header without the credential headers
+func redact(header http.Header) http.Header {
+	clean := header.Clone()
+	for _, name := range redactedHeaders {
+		clean.Del(name)
+	}
+	return clean
+}
+
+// redactQuery returns a copy of u with the credential query parameters
+// blanked. URLs without any are returned as they are, query order included.
+func redactQuery(u *url.URL) *url.URL {
+	query := u.Query()
+	changed := false
+	for name := range query {
+		for _, secret := range redactedParams {
+			if strings.EqualFold(name, secret) {
+				query[name] = []string{"REDACTED"}
+				changed = true
+			}
+		}
+	}
+
+	clean := *u
+	if changed {
+		clean.RawQuery = query.Encode()
+	}
+	return &clean
+}
+
+// Load reads every interaction from a cassette file
+func Load(path string) ([]Interaction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette: %w", err)
+	}
+	defer file.Close()
+
+	var interactions []Interaction
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 32*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("cassette line %d: %w", line, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	return interactions, nil
+}
+
+// Recorder is an http.RoundTripper that sends requests through Transport and
+// appends each exchange to a cassette file, one JSON object per line
+type Recorder struct {
+	// Transport sends the real requests; nil means http.DefaultTransport
+	Transport http.RoundTripper
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewRecorder creates or truncates the cassette at path. Call Close when the
+// session ends.
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cassette: %w", err)
+	}
+	return &Recorder{file: file}, nil
+}
+
+// RoundTrip sends req and records it with its response. Requests that fail
+// without a response are not recorded.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    redactQuery(req.URL).String(),
+			Header: redact(req.Header),
+			Body:   string(reqBody),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     redact(resp.Header),
+			Body:       string(respBody),
+		},
+	}
+	if err := r.write(interaction); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// write appends one interaction to the cassette
+func (r *Recorder) write(interaction Interaction) error {
+	line, err := json.Marshal(interaction)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Close closes the cassette file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// Player is an http.RoundTripper that answers requests from a cassette and
+// never touches the network
+type Player struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	played       []bool
+}
+
+// NewPlayer loads the cassette at path for playback
+func NewPlayer(path string) (*Player, error) {
+	interactions, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Player{
+		interactions: interactions,
+		played:       make([]bool, len(interactions)),
+	}, nil
+}
+
+// RoundTrip answers req with the first unplayed interaction for the same
+// method, path, query and body, so repeated requests see the responses in
+// recorded order. Credential query parameters are compared as recorded,
+// blanked. Requests whose query or body changes on every run
+// (timestamps, generated order IDs) fall back to an interaction for the same
+// method and path. Once every match has been played the last one is repeated.
+// Hosts are ignored, so a session recorded against the fake server or a
+// sandbox replays as well.
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = string(data)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	reqURL := redactQuery(req.URL)
+	exact := func(recorded Request) bool {
+		_, uri := requestURI(recorded.URL)
+		return recorded.Method == req.Method && uri == reqURL.RequestURI() && recorded.Body == body
+	}
+	samePath := func(recorded Request) bool {
+		path, _ := requestURI(recorded.URL)
+		return recorded.Method == req.Method && path == req.URL.Path
+	}
+
+	index := p.find(exact)
+	if index < 0 {
+		index = p.find(samePath)
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("cassette has no response for %s %s", req.Method, reqURL)
+	}
+	p.played[index] = true
+
+	recorded := p.interactions[index].Response
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	// The recorded Date no longer matches the local clock and would read as skew
+	header.Del("Date")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Origin returns the scheme and host of the first recorded request whose
+// path starts with prefix, or "" when there is none
+func (p *Player) Origin(prefix string) string {
+	for _, interaction := range p.interactions {
+		parsed, err := url.Parse(interaction.Request.URL)
+		if err == nil && parsed.Host != "" && strings.HasPrefix(parsed.Path, prefix) {
+			return parsed.Scheme + "://" + parsed.Host
+		}
+	}
+	return ""
+}
+
+// find returns the first unplayed interaction matching match, else the last
+// played one, else -1. Callers hold mu.
+func (p *Player) find(match func(Request) bool) int {
+	last := -1
+	for i, interaction := range p.interactions {
+		if !match(interaction.Request) {
+			continue
+		}
+		if !p.played[i] {
+			return i
+		}
+		last = i
+	}
+	return last
+}
+
+// requestURI returns the path and query of a recorded URL
+func requestURI(rawURL string) (path, pathAndQuery string) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", ""
+	}
+	return parsed.Path, parsed.RequestURI()
+}
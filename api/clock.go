@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MaxClockSkew is how far x-timestamp may drift from Robinhood's clock before
+// the API rejects a request as expired
+const MaxClockSkew = 30 * time.Second
+
+// clockResyncThreshold is how much a new measurement has to move the offset
+// before a rejected request is signed again with the corrected time
+const clockResyncThreshold = 5 * time.Second
+
+// clockSync tracks the offset between the local clock and the API's, measured
+// from the Date header of each response, so signed timestamps stay inside the
+// API's window even when the local clock drifts
+type clockSync struct {
+	mu       sync.Mutex
+	offset   time.Duration // server time minus local time
+	measured bool
+}
+
+// now returns the local time corrected by the measured offset
+func (s *clockSync) now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Now().Add(s.offset)
+}
+
+// skew returns the measured offset and whether any response has been seen
+func (s *clockSync) skew() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset, s.measured
+}
+
+// observe updates the offset from the Date header of a response to a request
+// sent at sent and answered at received, and returns how far the offset moved.
+// Responses without a usable Date header are ignored.
+func (s *clockSync) observe(header http.Header, sent, received time.Time) time.Duration {
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return 0
+	}
+
+	// Date has one-second resolution, so the server's clock was somewhere in
+	// the second after it; compare the middle of that second with the middle
+	// of the round trip
+	serverTime := date.Add(500 * time.Millisecond)
+	localTime := sent.Add(received.Sub(sent) / 2)
+	offset := serverTime.Sub(localTime).Round(time.Second)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	moved := offset - s.offset
+	s.offset = offset
+	s.measured = true
+	if moved < 0 {
+		moved = -moved
+	}
+	return moved
+}
+
+// ClockSkew returns how far the API's clock is ahead of the local clock (negative
+// when it is behind) as of the last response, and false before any response
+// has been received. Requests are signed with the corrected time either way.
+func (c *CryptoClient) ClockSkew() (time.Duration, bool) {
+	return c.clock.skew()
+}
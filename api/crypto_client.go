@@ -1,15 +1,19 @@
 package api
 
 import (
-	"bytes"
+	"context"
 	"crypto/ed25519"
+	"dazedtrader/decimal"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -20,24 +24,75 @@ func min(a, b int) int {
 	return b
 }
 
+// Default production endpoints
 const (
 	CryptoBaseURL = "https://trading.robinhood.com"
 	TradingURL    = "https://trading.robinhood.com/api/v1/crypto/trading"
 	MarketDataURL = "https://trading.robinhood.com/api/v1/crypto/marketdata"
 )
 
+// Quote fetching limits: symbols per best_bid_ask query (keeps the signed URL
+// short) and concurrent requests when a batch has to be split up
+const (
+	maxQuoteSymbols = 20
+	maxQuoteWorkers = 4
+)
+
+// API paths relative to the base URL
+const (
+	tradingPath    = "/api/v1/crypto/trading"
+	marketDataPath = "/api/v1/crypto/marketdata"
+)
+
 type CryptoClient struct {
 	HTTPClient *http.Client
 	APIKey     string
-	PrivateKey ed25519.PrivateKey
+
+	// Signer signs every request on behalf of APIKey
+	Signer Signer
+
+	// Endpoints, defaulting to the production constants above
+	BaseURL       string
+	TradingURL    string
+	MarketDataURL string
+
+	// Limiter throttles outgoing requests; MaxRetries bounds how often a
+	// throttled or failed request is resent
+	Limiter    *RateLimiter
+	MaxRetries int
+
+	// clock corrects signed timestamps for local clock drift
+	clock clockSync
+
+	// AccountNumber scopes holdings, order history and new orders to one
+	// account; empty means the API's default account
+	AccountNumber string
+
+	// Logger receives schema drift warnings; nil means slog.Default()
+	Logger *slog.Logger
+
+	driftMu   sync.Mutex
+	driftSeen map[string]bool
+	drift     []SchemaDrift
 }
 
 // NewCryptoClient creates a new Robinhood crypto API client
 // Input format: "apikey:privatekey" where privatekey is base64-encoded
 func NewCryptoClient(credentials string) *CryptoClient {
+	apiKey, privateKey, err := ParseCredentials(credentials)
+	if err != nil {
+		return nil
+	}
+
+	return NewCryptoClientWithSigner(apiKey, NewKeySigner(privateKey))
+}
+
+// ParseCredentials splits credentials in the "apikey:privatekey" format,
+// where the private key is a base64 Ed25519 key
+func ParseCredentials(credentials string) (string, ed25519.PrivateKey, error) {
 	parts := strings.Split(credentials, ":")
 	if len(parts) != 2 {
-		return nil
+		return "", nil, fmt.Errorf("credentials must be in the format apikey:privatekey")
 	}
 
 	apiKey := parts[0]
@@ -46,38 +101,60 @@ func NewCryptoClient(credentials string) *CryptoClient {
 	// Decode the private key from base64
 	privateKeyBytes, err := base64.StdEncoding.DecodeString(privateKeyB64)
 	if err != nil {
-		return nil
+		return "", nil, fmt.Errorf("private key is not valid base64: %w", err)
 	}
 
 	// Private key should be 64 bytes for Ed25519
 	if len(privateKeyBytes) != ed25519.PrivateKeySize {
-		return nil
+		return "", nil, fmt.Errorf("private key must be %d bytes, got %d", ed25519.PrivateKeySize, len(privateKeyBytes))
 	}
 
-	privateKey := ed25519.PrivateKey(privateKeyBytes)
+	return apiKey, ed25519.PrivateKey(privateKeyBytes), nil
+}
 
+// NewCryptoClientWithSigner creates a client for apiKey whose requests are
+// signed by signer, so the private key can stay outside the process
+func NewCryptoClientWithSigner(apiKey string, signer Signer) *CryptoClient {
 	return &CryptoClient{
 		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		APIKey:     apiKey,
-		PrivateKey: privateKey,
+		APIKey:        apiKey,
+		Signer:        signer,
+		BaseURL:       CryptoBaseURL,
+		TradingURL:    TradingURL,
+		MarketDataURL: MarketDataURL,
+		Limiter:       NewRateLimiter(RequestsPerMinute, RequestBurst),
+		MaxRetries:    3,
 	}
 }
 
+// SetBaseURL points the client at another host serving the same API paths,
+// such as a sandbox or the in-process server from package api/fake
+func (c *CryptoClient) SetBaseURL(baseURL string) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	c.BaseURL = baseURL
+	c.TradingURL = baseURL + tradingPath
+	c.MarketDataURL = baseURL + marketDataPath
+}
+
 // Crypto data structures based on actual API responses
 type CryptoAccount struct {
-	AccountNumber      string `json:"account_number"`
-	Status            string `json:"status"`
-	BuyingPower       string `json:"buying_power"`
-	BuyingPowerCurrency string `json:"buying_power_currency"`
+	AccountNumber       string          `json:"account_number"`
+	Status              string          `json:"status"`
+	BuyingPower         decimal.Decimal `json:"buying_power"`
+	BuyingPowerCurrency string          `json:"buying_power_currency"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields the client doesn't know, as raw JSON
 }
 
 type CryptoHolding struct {
-	AccountNumber                string  `json:"account_number"`
-	AssetCode                    string  `json:"asset_code"`
-	TotalQuantity                float64 `json:"total_quantity"`
-	QuantityAvailableForTrading  float64 `json:"quantity_available_for_trading"`
+	AccountNumber               string          `json:"account_number"`
+	AssetCode                   string          `json:"asset_code"`
+	TotalQuantity               decimal.Decimal `json:"total_quantity"`
+	QuantityAvailableForTrading decimal.Decimal `json:"quantity_available_for_trading"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields the client doesn't know, as raw JSON
 }
 
 // Pagination wrapper for API responses
@@ -88,27 +165,81 @@ type PaginatedResponse[T any] struct {
 }
 
 type BestBidAsk struct {
-	Symbol                    string  `json:"symbol"`
-	Price                     float64 `json:"price"`
-	BidPrice                  float64 `json:"bid_inclusive_of_sell_spread"`
-	SellSpread               float64 `json:"sell_spread"`
-	AskPrice                  float64 `json:"ask_inclusive_of_buy_spread"`
-	BuySpread                float64 `json:"buy_spread"`
-	Timestamp                string  `json:"timestamp"`
+	Symbol     string          `json:"symbol"`
+	Price      decimal.Decimal `json:"price"`
+	BidPrice   decimal.Decimal `json:"bid_inclusive_of_sell_spread"`
+	SellSpread decimal.Decimal `json:"sell_spread"`
+	AskPrice   decimal.Decimal `json:"ask_inclusive_of_buy_spread"`
+	BuySpread  decimal.Decimal `json:"buy_spread"`
+	Timestamp  string          `json:"timestamp"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields the client doesn't know, as raw JSON
+}
+
+// EstimatedPrice is a quote for executing a given quantity. Price is the mid
+// price; the bid and ask include the spread for an order of that size.
+type EstimatedPrice struct {
+	Symbol     string          `json:"symbol"`
+	Side       string          `json:"side"`
+	Price      decimal.Decimal `json:"price"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	BidPrice   decimal.Decimal `json:"bid_inclusive_of_sell_spread"`
+	SellSpread decimal.Decimal `json:"sell_spread"`
+	AskPrice   decimal.Decimal `json:"ask_inclusive_of_buy_spread"`
+	BuySpread  decimal.Decimal `json:"buy_spread"`
+	Timestamp  string          `json:"timestamp"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields the client doesn't know, as raw JSON
+}
+
+// ExecutionPrice returns the price a buy or sell order of this size would fill at
+func (e EstimatedPrice) ExecutionPrice(orderSide string) decimal.Decimal {
+	if orderSide == "sell" {
+		return e.BidPrice
+	}
+	return e.AskPrice
 }
 
 type CryptoOrder struct {
-	ID                string  `json:"id"`
-	AccountNumber     string  `json:"account_number"`
-	Symbol            string  `json:"symbol"`
-	ClientOrderID     string  `json:"client_order_id"`
-	Side              string  `json:"side"`
-	Type              string  `json:"type"`
-	State             string  `json:"state"`
-	AveragePrice      float64 `json:"average_price"`
-	FilledAssetQuantity float64 `json:"filled_asset_quantity"`
-	CreatedAt         string  `json:"created_at"`
-	UpdatedAt         string  `json:"updated_at"`
+	ID                  string          `json:"id"`
+	AccountNumber       string          `json:"account_number"`
+	Symbol              string          `json:"symbol"`
+	ClientOrderID       string          `json:"client_order_id"`
+	Side                string          `json:"side"`
+	Type                string          `json:"type"`
+	State               string          `json:"state"`
+	AveragePrice        decimal.Decimal `json:"average_price"`
+	FilledAssetQuantity decimal.Decimal `json:"filled_asset_quantity"`
+	Quantity            decimal.Decimal `json:"-"` // Ordered asset quantity from the order config
+	QuoteAmount         decimal.Decimal `json:"-"` // Ordered notional for orders sized in USD
+	Executions          []Execution     `json:"executions"`
+	CreatedAt           string          `json:"created_at"`
+	UpdatedAt           string          `json:"updated_at"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields the client doesn't know, as raw JSON
+}
+
+// Execution is a single fill of an order. EffectivePrice includes the spread.
+type Execution struct {
+	EffectivePrice decimal.Decimal `json:"effective_price"`
+	Quantity       decimal.Decimal `json:"quantity"`
+	Timestamp      string          `json:"timestamp"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields the client doesn't know, as raw JSON
+}
+
+// Notional returns the USD value of the fill
+func (e Execution) Notional() decimal.Decimal {
+	return e.EffectivePrice.Mul(e.Quantity)
+}
+
+// IsTerminal reports whether the order can no longer change state
+func (o CryptoOrder) IsTerminal() bool {
+	switch o.State {
+	case "filled", "canceled", "failed":
+		return true
+	}
+	return false
 }
 
 type OrderRequest struct {
@@ -120,9 +251,106 @@ type OrderRequest struct {
 	CurrencyID  string `json:"currency_id"`
 }
 
-// makeRequest makes HTTP requests to Robinhood crypto API
-func (c *CryptoClient) makeRequest(method, endpoint string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
+// OrderParams describes a new order. Quantities and prices are decimal
+// strings and are sent exactly as given.
+type OrderParams struct {
+	ClientOrderID string
+	Side          string // "buy" or "sell"
+	Type          string // "market", "limit", "stop_loss" or "stop_limit"
+	Symbol        string
+	Quantity      string // Asset quantity; leave empty when QuoteAmount is set
+	QuoteAmount   string // Order size in the quote currency (USD) instead of Quantity
+	LimitPrice    string // Required for limit and stop_limit orders
+	StopPrice     string // Required for stop_loss and stop_limit orders
+	TimeInForce   string // "gtc" (default) or "gfd"; not sent for market orders
+	AccountNumber string // Account to trade in; defaults to the client's AccountNumber
+}
+
+// Validate checks that the order can be sent, without sending it
+func (p OrderParams) Validate() error {
+	_, err := p.requestBody()
+	return err
+}
+
+// requestBody builds the order request according to Robinhood API docs
+func (p OrderParams) requestBody() (map[string]interface{}, error) {
+	if (p.Quantity == "") == (p.QuoteAmount == "") {
+		return nil, fmt.Errorf("exactly one of asset quantity or quote amount is required")
+	}
+
+	// Every order config sizes the order the same way
+	size := func(config map[string]interface{}) map[string]interface{} {
+		if p.QuoteAmount != "" {
+			config["quote_amount"] = p.QuoteAmount
+		} else {
+			config["asset_quantity"] = p.Quantity
+		}
+		return config
+	}
+
+	timeInForce := p.TimeInForce
+	if timeInForce == "" {
+		timeInForce = "gtc"
+	}
+
+	orderRequest := map[string]interface{}{
+		"client_order_id": p.ClientOrderID,
+		"side":            p.Side,
+		"type":            p.Type,
+		"symbol":          p.Symbol,
+	}
+	if p.AccountNumber != "" {
+		orderRequest["account_number"] = p.AccountNumber
+	}
+
+	// Add order type specific configuration
+	switch p.Type {
+	case "market":
+		orderRequest["market_order_config"] = size(map[string]interface{}{})
+	case "limit":
+		if p.LimitPrice == "" {
+			return nil, fmt.Errorf("limit price is required for limit orders")
+		}
+		orderRequest["limit_order_config"] = size(map[string]interface{}{
+			"limit_price":   p.LimitPrice,
+			"time_in_force": timeInForce,
+		})
+	case "stop_loss":
+		if p.StopPrice == "" {
+			return nil, fmt.Errorf("stop price is required for stop-loss orders")
+		}
+		orderRequest["stop_loss_order_config"] = size(map[string]interface{}{
+			"stop_price":    p.StopPrice,
+			"time_in_force": timeInForce,
+		})
+	case "stop_limit":
+		if p.StopPrice == "" || p.LimitPrice == "" {
+			return nil, fmt.Errorf("stop and limit prices are required for stop-limit orders")
+		}
+		orderRequest["stop_limit_order_config"] = size(map[string]interface{}{
+			"limit_price":   p.LimitPrice,
+			"stop_price":    p.StopPrice,
+			"time_in_force": timeInForce,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported order type %q", p.Type)
+	}
+
+	return orderRequest, nil
+}
+
+// makeRequest makes HTTP requests to Robinhood crypto API. The request is
+// aborted as soon as ctx is canceled or its deadline passes.
+//
+// Every attempt first takes a token from the client's rate limiter. A 429 is
+// retried after the Retry-After delay (or an exponential backoff), and so is
+// a 5xx on GET; other methods are never resent after a server error because
+// the request may already have taken effect.
+//
+// Each response's Date header updates the client's clock offset. A request
+// rejected as unauthenticated is signed and sent once more when that offset
+// has just moved, since the rejection was most likely an expired timestamp.
+func (c *CryptoClient) makeRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
 	var bodyString string
 
 	if body != nil {
@@ -130,26 +358,89 @@ func (c *CryptoClient) makeRequest(method, endpoint string, body interface{}) (*
 		if err != nil {
 			return nil, err
 		}
-		reqBody = bytes.NewBuffer(bodyBytes)
 		bodyString = string(bodyBytes)
 	}
 
-	req, err := http.NewRequest(method, endpoint, reqBody)
+	resynced := false
+	for attempt := 0; ; attempt++ {
+		if c.Limiter != nil {
+			if err := c.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := c.newSignedRequest(ctx, method, endpoint, bodyString)
+		if err != nil {
+			return nil, err
+		}
+
+		sent := time.Now()
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		moved := c.clock.observe(resp.Header, sent, time.Now())
+		if resp.StatusCode == http.StatusUnauthorized && !resynced && moved >= clockResyncThreshold {
+			resynced = true
+			resp.Body.Close()
+			attempt--
+			continue
+		}
+
+		retryable := resp.StatusCode == http.StatusTooManyRequests ||
+			(method == http.MethodGet && resp.StatusCode >= 500)
+		if !retryable || attempt >= c.MaxRetries {
+			return resp, nil
+		}
+
+		delay, ok := retryAfter(resp.Header)
+		if !ok {
+			delay = backoff(attempt)
+		}
+		resp.Body.Close()
+
+		// Hold back every caller sharing the limiter, not just this one
+		if resp.StatusCode == http.StatusTooManyRequests && c.Limiter != nil {
+			c.Limiter.Pause(delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// newSignedRequest builds a request carrying the Ed25519 authentication headers
+func (c *CryptoClient) newSignedRequest(ctx context.Context, method, endpoint, bodyString string) (*http.Request, error) {
+	var reqBody io.Reader
+	if bodyString != "" {
+		reqBody = strings.NewReader(bodyString)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate timestamp (Unix timestamp in seconds)
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	// Generate timestamp (Unix timestamp in seconds, on the API's clock)
+	timestamp := strconv.FormatInt(c.clock.now().Unix(), 10)
 
 	// Extract path from full URL
-	path := strings.TrimPrefix(endpoint, CryptoBaseURL)
+	path := strings.TrimPrefix(endpoint, c.BaseURL)
 
 	// Create message to sign: api_key + timestamp + path + method + body
 	message := c.APIKey + timestamp + path + method + bodyString
 
 	// Sign the message with Ed25519
-	signature := ed25519.Sign(c.PrivateKey, []byte(message))
+	signature, err := c.Signer.Sign(ctx, []byte(message))
+	if err != nil {
+		return nil, &SignError{Err: err}
+	}
 	signatureB64 := base64.StdEncoding.EncodeToString(signature)
 
 	// Set the required headers for Robinhood crypto API authentication
@@ -160,468 +451,449 @@ func (c *CryptoClient) makeRequest(method, endpoint string, body interface{}) (*
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "DazedTrader/1.0")
 
+	return req, nil
+}
 
-	return c.HTTPClient.Do(req)
+// RateLimitRemaining returns how many requests can be sent right now without
+// waiting, or -1 when the client has no limiter
+func (c *CryptoClient) RateLimitRemaining() int {
+	if c.Limiter == nil {
+		return -1
+	}
+	return c.Limiter.Remaining()
 }
 
-// GetCryptoAccount retrieves crypto account information
+// GetCryptoAccount retrieves the active account: the one matching
+// AccountNumber, or the first account when AccountNumber is empty
 func (c *CryptoClient) GetCryptoAccount() (*CryptoAccount, error) {
-	resp, err := c.makeRequest("GET", TradingURL+"/accounts/", nil)
+	return c.GetCryptoAccountContext(context.Background())
+}
+
+// GetCryptoAccountContext is like GetCryptoAccount but aborts when ctx is done
+func (c *CryptoClient) GetCryptoAccountContext(ctx context.Context) (*CryptoAccount, error) {
+	accounts, err := c.GetCryptoAccountsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("no crypto account found for this API key")
 	}
-
-	var account CryptoAccount
-	if err := json.NewDecoder(resp.Body).Decode(&account); err != nil {
-		return nil, err
+	if c.AccountNumber == "" {
+		return &accounts[0], nil
 	}
-
-	return &account, nil
+	for i := range accounts {
+		if accounts[i].AccountNumber == c.AccountNumber {
+			return &accounts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("account %s not found for this API key", c.AccountNumber)
 }
 
-// GetCryptoHoldings retrieves all crypto holdings
-func (c *CryptoClient) GetCryptoHoldings() ([]CryptoHolding, error) {
-	resp, err := c.makeRequest("GET", TradingURL+"/holdings/", nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// GetCryptoAccounts retrieves every crypto account the API key can trade
+func (c *CryptoClient) GetCryptoAccounts() ([]CryptoAccount, error) {
+	return c.GetCryptoAccountsContext(context.Background())
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
+// GetCryptoAccountsContext is like GetCryptoAccounts but aborts when ctx is done
+func (c *CryptoClient) GetCryptoAccountsContext(ctx context.Context) ([]CryptoAccount, error) {
+	return c.CryptoAccountsPages().AllContext(ctx, 0)
+}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+// GetCryptoHoldings retrieves all crypto holdings, following every page
+func (c *CryptoClient) GetCryptoHoldings() ([]CryptoHolding, error) {
+	return c.GetCryptoHoldingsContext(context.Background())
+}
 
-	var mapResponse struct {
-		Results []map[string]interface{} `json:"results"`
-	}
+// GetCryptoHoldingsContext is like GetCryptoHoldings but aborts when ctx is done
+func (c *CryptoClient) GetCryptoHoldingsContext(ctx context.Context) ([]CryptoHolding, error) {
+	return c.CryptoHoldingsPages().AllContext(ctx, 0)
+}
 
-	if err := json.Unmarshal(bodyBytes, &mapResponse); err == nil {
-		// Convert map to holdings using the actual field names
-		var holdings []CryptoHolding
-		for _, result := range mapResponse.Results {
-			holding := CryptoHolding{}
+// GetBestBidAsk retrieves best bid/ask prices for cryptocurrencies
+func (c *CryptoClient) GetBestBidAsk(symbols []string) ([]BestBidAsk, error) {
+	return c.GetBestBidAskContext(context.Background(), symbols)
+}
 
-			// Parse the exact fields from Robinhood API documentation
-			if val, ok := result["account_number"].(string); ok {
-				holding.AccountNumber = val
-			}
-			if val, ok := result["asset_code"].(string); ok {
-				holding.AssetCode = val
-			}
+// GetBestBidAskContext is like GetBestBidAsk but aborts when ctx is done.
+//
+// Symbols are requested in batches of maxQuoteSymbols per round trip. If a
+// batch is rejected (for example because one symbol is unknown), its symbols
+// are re-requested individually with bounded concurrency and the symbols
+// that still fail are left out of the result. An error is returned only
+// when no quote could be fetched at all.
+func (c *CryptoClient) GetBestBidAskContext(ctx context.Context, symbols []string) ([]BestBidAsk, error) {
+	if len(symbols) == 0 {
+		return c.getBestBidAskBatch(ctx, nil)
+	}
+
+	var quotes []BestBidAsk
+	var firstErr error
+	for start := 0; start < len(symbols); start += maxQuoteSymbols {
+		end := start + maxQuoteSymbols
+		if end > len(symbols) {
+			end = len(symbols)
+		}
+		chunk := symbols[start:end]
 
-			// Parse total_quantity (API returns as string)
-			if val, ok := result["total_quantity"].(string); ok {
-				if parsed, err := strconv.ParseFloat(val, 64); err == nil {
-					holding.TotalQuantity = parsed
-				}
-			} else if val, ok := result["total_quantity"].(float64); ok {
-				holding.TotalQuantity = val
+		batch, err := c.getBestBidAskBatch(ctx, chunk)
+		if err != nil && ctx.Err() == nil && !IsAuthError(err) && len(chunk) > 1 {
+			batch, err = c.getBestBidAskEach(ctx, chunk)
+		}
+		if err != nil {
+			if ctx.Err() != nil || IsAuthError(err) {
+				return quotes, err
 			}
-
-			// Parse quantity_available_for_trading (API returns as string)
-			if val, ok := result["quantity_available_for_trading"].(string); ok {
-				if parsed, err := strconv.ParseFloat(val, 64); err == nil {
-					holding.QuantityAvailableForTrading = parsed
-				}
-			} else if val, ok := result["quantity_available_for_trading"].(float64); ok {
-				holding.QuantityAvailableForTrading = val
+			// Every symbol of the chunk failed; leave them out like the rest
+			if firstErr == nil {
+				firstErr = err
 			}
-
-			holdings = append(holdings, holding)
+			continue
 		}
-		return holdings, nil
+		quotes = append(quotes, batch...)
 	}
 
-	// Fallback to structured decode
-	var response PaginatedResponse[CryptoHolding]
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		return nil, err
+	if len(quotes) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return quotes, nil
+}
+
+// getBestBidAskBatch fetches quotes for all symbols in a single query,
+// following next links if the API splits the response into pages
+func (c *CryptoClient) getBestBidAskBatch(ctx context.Context, symbols []string) ([]BestBidAsk, error) {
+	endpoint := c.MarketDataURL + "/best_bid_ask/"
+	if len(symbols) > 0 {
+		endpoint += "?" + url.Values{"symbol": symbols}.Encode()
 	}
 
-	return response.Results, nil
+	return newPaginator(c, endpoint, pageDecoder(c, "quote", decodeQuote)).AllContext(ctx, 0)
 }
 
-// GetBestBidAsk retrieves best bid/ask prices for cryptocurrencies
-func (c *CryptoClient) GetBestBidAsk(symbols []string) ([]BestBidAsk, error) {
-	queryParams := ""
+// getBestBidAskEach fetches one quote per request, running at most
+// maxQuoteWorkers requests at a time. It fails only if every symbol failed.
+func (c *CryptoClient) getBestBidAskEach(ctx context.Context, symbols []string) ([]BestBidAsk, error) {
+	results := make([][]BestBidAsk, len(symbols))
+	errs := make([]error, len(symbols))
+
+	sem := make(chan struct{}, maxQuoteWorkers)
+	var wg sync.WaitGroup
 	for i, symbol := range symbols {
-		if i == 0 {
-			queryParams = "?symbol=" + symbol
-		} else {
-			queryParams += "&symbol=" + symbol
+		wg.Add(1)
+		go func(i int, symbol string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i], errs[i] = c.getBestBidAskBatch(ctx, []string{symbol})
+		}(i, symbol)
+	}
+	wg.Wait()
+
+	var quotes []BestBidAsk
+	var firstErr error
+	for i := range symbols {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
 		}
+		quotes = append(quotes, results[i]...)
 	}
 
-	endpoint := MarketDataURL + "/best_bid_ask/" + queryParams
-
-	resp, err := c.makeRequest("GET", endpoint, nil)
-	if err != nil {
-		return nil, err
+	if len(quotes) == 0 && firstErr != nil {
+		return nil, firstErr
 	}
-	defer resp.Body.Close()
+	return quotes, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
+// GetEstimatedPrice quotes the execution price of symbol for each quantity.
+// side is "bid" (selling), "ask" (buying) or "both".
+func (c *CryptoClient) GetEstimatedPrice(symbol, side string, quantities []decimal.Decimal) ([]EstimatedPrice, error) {
+	return c.GetEstimatedPriceContext(context.Background(), symbol, side, quantities)
+}
 
-	// Read the response body first for better error handling
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+// GetEstimatedPriceContext is like GetEstimatedPrice but aborts when ctx is done
+func (c *CryptoClient) GetEstimatedPriceContext(ctx context.Context, symbol, side string, quantities []decimal.Decimal) ([]EstimatedPrice, error) {
+	if len(quantities) == 0 {
+		return nil, fmt.Errorf("at least one quantity is required")
 	}
 
-	var response PaginatedResponse[BestBidAsk]
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %v. Response body: %s", err, string(body))
+	formatted := make([]string, len(quantities))
+	for i, quantity := range quantities {
+		formatted[i] = quantity.String()
 	}
 
-	return response.Results, nil
+	query := url.Values{}
+	query.Set("symbol", symbol)
+	query.Set("side", side)
+	query.Set("quantity", strings.Join(formatted, ","))
+
+	endpoint := c.MarketDataURL + "/estimated_price/?" + query.Encode()
+	return newPaginator(c, endpoint, pageDecoder(c, "estimated price", decodeEstimatedPrice)).AllContext(ctx, 0)
 }
 
-// GetCryptoOrders retrieves crypto order history
+// GetCryptoOrders retrieves the complete crypto order history
 func (c *CryptoClient) GetCryptoOrders() ([]CryptoOrder, error) {
-	resp, err := c.makeRequest("GET", TradingURL+"/orders/", nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	return c.GetCryptoOrdersContext(context.Background())
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+// GetCryptoOrdersContext is like GetCryptoOrders but aborts when ctx is done
+func (c *CryptoClient) GetCryptoOrdersContext(ctx context.Context) ([]CryptoOrder, error) {
+	return c.CryptoOrdersPages(OrderFilter{}).AllContext(ctx, 0)
+}
+
+// OrderFilter narrows an order history query on the server. Empty fields
+// and zero times are left out of the query.
+type OrderFilter struct {
+	Symbol string // e.g. "BTC-USD"
+	Side   string // "buy" or "sell"
+	State  string // "open", "partially_filled", "filled", "canceled" or "failed"
+	Type   string // "market", "limit", "stop_loss" or "stop_limit"
+
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+
+	Limit int // Page size, 0 for the API default
+
+	AccountNumber string // Account to list; defaults to the client's AccountNumber
+}
+
+// IsZero reports whether the filter matches every order of the account
+func (f OrderFilter) IsZero() bool {
+	f.Limit = 0
+	f.AccountNumber = ""
+	return f == OrderFilter{}
+}
+
+// query maps the filter onto the orders endpoint's query parameters
+func (f OrderFilter) query() url.Values {
+	query := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	setTime := func(key string, t time.Time) {
+		if !t.IsZero() {
+			query.Set(key, t.UTC().Format(time.RFC3339))
+		}
 	}
 
-	var response PaginatedResponse[CryptoOrder]
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, err
+	set("account_number", f.AccountNumber)
+	set("symbol", f.Symbol)
+	set("side", f.Side)
+	set("state", f.State)
+	set("type", f.Type)
+	setTime("created_at_start", f.CreatedAfter)
+	setTime("created_at_end", f.CreatedBefore)
+	setTime("updated_at_start", f.UpdatedAfter)
+	setTime("updated_at_end", f.UpdatedBefore)
+	if f.Limit > 0 {
+		query.Set("limit", strconv.Itoa(f.Limit))
 	}
 
-	return response.Results, nil
+	return query
 }
 
-// GetCryptoOrdersWithParams retrieves crypto order history with query parameters
-func (c *CryptoClient) GetCryptoOrdersWithParams(limit int) ([]CryptoOrder, error) {
-	// Build query with parameters
-	endpoint := TradingURL + "/orders/"
-	if limit > 0 {
-		endpoint += fmt.Sprintf("?limit=%d", limit)
-	}
+// GetCryptoOrdersWithParams retrieves the first page of orders matching filter
+func (c *CryptoClient) GetCryptoOrdersWithParams(filter OrderFilter) ([]CryptoOrder, error) {
+	return c.GetCryptoOrdersWithParamsContext(context.Background(), filter)
+}
+
+// GetCryptoOrdersWithParamsContext is like GetCryptoOrdersWithParams but aborts when ctx is done
+func (c *CryptoClient) GetCryptoOrdersWithParamsContext(ctx context.Context, filter OrderFilter) ([]CryptoOrder, error) {
+	return c.CryptoOrdersPages(filter).NextContext(ctx)
+}
+
+// GetAllCryptoOrders follows the order history cursors until maxOrders orders
+// have been collected (0 means every page)
+func (c *CryptoClient) GetAllCryptoOrders(maxOrders int) ([]CryptoOrder, error) {
+	return c.GetAllCryptoOrdersContext(context.Background(), maxOrders)
+}
 
-	resp, err := c.makeRequest("GET", endpoint, nil)
+// GetAllCryptoOrdersContext is like GetAllCryptoOrders but aborts when ctx is done
+func (c *CryptoClient) GetAllCryptoOrdersContext(ctx context.Context, maxOrders int) ([]CryptoOrder, error) {
+	return c.CryptoOrdersPages(OrderFilter{}).AllContext(ctx, maxOrders)
+}
+
+// GetCryptoOrder retrieves a single order by ID
+func (c *CryptoClient) GetCryptoOrder(orderID string) (*CryptoOrder, error) {
+	return c.GetCryptoOrderContext(context.Background(), orderID)
+}
+
+// GetCryptoOrderContext is like GetCryptoOrder but aborts when ctx is done
+func (c *CryptoClient) GetCryptoOrderContext(ctx context.Context, orderID string) (*CryptoOrder, error) {
+	endpoint := fmt.Sprintf("%s/orders/%s/", c.TradingURL, url.PathEscape(orderID))
+
+	resp, err := c.makeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp)
 	}
 
-	// Read response body for better error handling
-	bodyBytes, err := io.ReadAll(resp.Body)
+	return c.readOrder(resp)
+}
+
+// readOrder decodes an order response body
+func (c *CryptoClient) readOrder(resp *http.Response) (*CryptoOrder, error) {
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %v", err)
 	}
 
-	// Try to parse the response structure first
-	var rawResponse map[string]interface{}
-	if err := json.Unmarshal(bodyBytes, &rawResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %v", err)
-	}
-
-	// Extract orders from results field
-	var orders []CryptoOrder
-	if results, ok := rawResponse["results"].([]interface{}); ok {
-		for _, result := range results {
-			if orderMap, ok := result.(map[string]interface{}); ok {
-				order := CryptoOrder{}
-
-				// Parse all fields according to Robinhood API documentation
-				if val, ok := orderMap["id"].(string); ok {
-					order.ID = val
-				}
-				if val, ok := orderMap["account_number"].(string); ok {
-					order.AccountNumber = val
-				}
-				if val, ok := orderMap["symbol"].(string); ok {
-					order.Symbol = val
-				}
-				if val, ok := orderMap["client_order_id"].(string); ok {
-					order.ClientOrderID = val
-				}
-				if val, ok := orderMap["side"].(string); ok {
-					order.Side = val
-				}
-				if val, ok := orderMap["type"].(string); ok {
-					order.Type = val
-				}
-				if val, ok := orderMap["state"].(string); ok {
-					order.State = val
-				}
-				if val, ok := orderMap["created_at"].(string); ok {
-					order.CreatedAt = val
-				}
-				if val, ok := orderMap["updated_at"].(string); ok {
-					order.UpdatedAt = val
-				}
-
-				// Handle average_price (can be string or float)
-				if val, ok := orderMap["average_price"].(string); ok {
-					if parsed, err := strconv.ParseFloat(val, 64); err == nil {
-						order.AveragePrice = parsed
-					}
-				} else if val, ok := orderMap["average_price"].(float64); ok {
-					order.AveragePrice = val
-				}
-
-				// Handle filled_asset_quantity (can be string or float)
-				if val, ok := orderMap["filled_asset_quantity"].(string); ok {
-					if parsed, err := strconv.ParseFloat(val, 64); err == nil {
-						order.FilledAssetQuantity = parsed
-					}
-				} else if val, ok := orderMap["filled_asset_quantity"].(float64); ok {
-					order.FilledAssetQuantity = val
-				}
-
-				// Also check for executions array if filled_asset_quantity is not present
-				if order.FilledAssetQuantity == 0 {
-					if executions, ok := orderMap["executions"].([]interface{}); ok && len(executions) > 0 {
-						// Sum up executed quantities
-						for _, exec := range executions {
-							if execMap, ok := exec.(map[string]interface{}); ok {
-								if qty, ok := execMap["quantity"].(string); ok {
-									if parsed, err := strconv.ParseFloat(qty, 64); err == nil {
-										order.FilledAssetQuantity += parsed
-									}
-								} else if qty, ok := execMap["quantity"].(float64); ok {
-									order.FilledAssetQuantity += qty
-								}
-							}
-						}
-					}
-				}
-
-				// Check for order config fields to extract quantity if filled_asset_quantity is 0
-				if order.FilledAssetQuantity == 0 {
-					// Check market_order_config
-					if marketConfig, ok := orderMap["market_order_config"].(map[string]interface{}); ok {
-						if qty, ok := marketConfig["asset_quantity"].(string); ok {
-							if parsed, err := strconv.ParseFloat(qty, 64); err == nil {
-								order.FilledAssetQuantity = parsed
-							}
-						} else if qty, ok := marketConfig["asset_quantity"].(float64); ok {
-							order.FilledAssetQuantity = qty
-						}
-					}
-					// Check limit_order_config
-					if limitConfig, ok := orderMap["limit_order_config"].(map[string]interface{}); ok {
-						if qty, ok := limitConfig["asset_quantity"].(string); ok {
-							if parsed, err := strconv.ParseFloat(qty, 64); err == nil {
-								order.FilledAssetQuantity = parsed
-							}
-						} else if qty, ok := limitConfig["asset_quantity"].(float64); ok {
-							order.FilledAssetQuantity = qty
-						}
-						// Also get limit price for average price if not set
-						if order.AveragePrice == 0 {
-							if price, ok := limitConfig["limit_price"].(string); ok {
-								if parsed, err := strconv.ParseFloat(price, 64); err == nil {
-									order.AveragePrice = parsed
-								}
-							} else if price, ok := limitConfig["limit_price"].(float64); ok {
-								order.AveragePrice = price
-							}
-						}
-					}
-				}
-
-				orders = append(orders, order)
-			}
-		}
+	order, err := decodeObject(c, "order", body, decodeOrder)
+	if err != nil {
+		return nil, err
 	}
-
-	return orders, nil
+	return &order, nil
 }
 
 // PlaceCryptoOrder places a new crypto order (legacy)
 func (c *CryptoClient) PlaceCryptoOrder(order OrderRequest) (*CryptoOrder, error) {
-	resp, err := c.makeRequest("POST", TradingURL+"/orders/", order)
+	return c.PlaceCryptoOrderContext(context.Background(), order)
+}
+
+// PlaceCryptoOrderContext is like PlaceCryptoOrder but aborts when ctx is done
+func (c *CryptoClient) PlaceCryptoOrderContext(ctx context.Context, order OrderRequest) (*CryptoOrder, error) {
+	resp, err := c.makeRequest(ctx, "POST", c.TradingURL+"/orders/", order)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
-
-	var cryptoOrder CryptoOrder
-	if err := json.NewDecoder(resp.Body).Decode(&cryptoOrder); err != nil {
-		return nil, err
+		return nil, newAPIError(resp)
 	}
 
-	return &cryptoOrder, nil
+	return c.readOrder(resp)
 }
 
 // PlaceCryptoOrderNew places a new crypto order using the correct API format
 func (c *CryptoClient) PlaceCryptoOrderNew(clientOrderID, side, orderType, symbol, quantity, price string) (*CryptoOrder, error) {
-	// Build order request according to Robinhood API docs
-	orderRequest := map[string]interface{}{
-		"client_order_id": clientOrderID,
-		"side":           side,
-		"type":           orderType,
-		"symbol":         symbol,
-	}
+	return c.PlaceCryptoOrderNewContext(context.Background(), clientOrderID, side, orderType, symbol, quantity, price)
+}
 
-	// Add order type specific configuration
-	if orderType == "market" {
-		orderRequest["market_order_config"] = map[string]interface{}{
-			"asset_quantity": quantity,
-		}
-	} else if orderType == "limit" {
-		orderRequest["limit_order_config"] = map[string]interface{}{
-			"asset_quantity": quantity,
-			"limit_price":    price,
-			"time_in_force":  "gtc",
-		}
-	}
+// PlaceCryptoOrderNewContext is like PlaceCryptoOrderNew but aborts when ctx is done
+func (c *CryptoClient) PlaceCryptoOrderNewContext(ctx context.Context, clientOrderID, side, orderType, symbol, quantity, price string) (*CryptoOrder, error) {
+	return c.PlaceCryptoOrderWithParamsContext(ctx, OrderParams{
+		ClientOrderID: clientOrderID,
+		Side:          side,
+		Type:          orderType,
+		Symbol:        symbol,
+		Quantity:      quantity,
+		LimitPrice:    price,
+	})
+}
 
-	resp, err := c.makeRequest("POST", TradingURL+"/orders/", orderRequest)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// PlaceCryptoOrderWithParams places a market, limit, stop-loss or stop-limit order
+func (c *CryptoClient) PlaceCryptoOrderWithParams(params OrderParams) (*CryptoOrder, error) {
+	return c.PlaceCryptoOrderWithParamsContext(context.Background(), params)
+}
 
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+// PlaceCryptoOrderWithParamsContext is like PlaceCryptoOrderWithParams but aborts when ctx is done
+func (c *CryptoClient) PlaceCryptoOrderWithParamsContext(ctx context.Context, params OrderParams) (*CryptoOrder, error) {
+	if params.AccountNumber == "" {
+		params.AccountNumber = c.AccountNumber
 	}
-
-	// Read response body for manual parsing
-	bodyBytes, err := io.ReadAll(resp.Body)
+	orderRequest, err := params.requestBody()
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse as map first to handle string/float conversion
-	var orderMap map[string]interface{}
-	if err := json.Unmarshal(bodyBytes, &orderMap); err != nil {
+	resp, err := c.makeRequest(ctx, "POST", c.TradingURL+"/orders/", orderRequest)
+	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	// Convert map to CryptoOrder with proper type handling
-	cryptoOrder := CryptoOrder{}
-
-	if val, ok := orderMap["id"].(string); ok {
-		cryptoOrder.ID = val
-	}
-	if val, ok := orderMap["account_number"].(string); ok {
-		cryptoOrder.AccountNumber = val
-	}
-	if val, ok := orderMap["symbol"].(string); ok {
-		cryptoOrder.Symbol = val
-	}
-	if val, ok := orderMap["client_order_id"].(string); ok {
-		cryptoOrder.ClientOrderID = val
-	}
-	if val, ok := orderMap["side"].(string); ok {
-		cryptoOrder.Side = val
-	}
-	if val, ok := orderMap["type"].(string); ok {
-		cryptoOrder.Type = val
-	}
-	if val, ok := orderMap["state"].(string); ok {
-		cryptoOrder.State = val
-	}
-	if val, ok := orderMap["created_at"].(string); ok {
-		cryptoOrder.CreatedAt = val
-	}
-	if val, ok := orderMap["updated_at"].(string); ok {
-		cryptoOrder.UpdatedAt = val
-	}
-
-	// Handle average_price (can be string or float)
-	if val, ok := orderMap["average_price"].(string); ok {
-		if parsed, err := strconv.ParseFloat(val, 64); err == nil {
-			cryptoOrder.AveragePrice = parsed
-		}
-	} else if val, ok := orderMap["average_price"].(float64); ok {
-		cryptoOrder.AveragePrice = val
-	}
-
-	// Handle filled_asset_quantity (can be string or float)
-	if val, ok := orderMap["filled_asset_quantity"].(string); ok {
-		if parsed, err := strconv.ParseFloat(val, 64); err == nil {
-			cryptoOrder.FilledAssetQuantity = parsed
-		}
-	} else if val, ok := orderMap["filled_asset_quantity"].(float64); ok {
-		cryptoOrder.FilledAssetQuantity = val
+	if resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError(resp)
 	}
 
-	return &cryptoOrder, nil
+	return c.readOrder(resp)
 }
 
 // CancelCryptoOrder cancels an existing crypto order
 func (c *CryptoClient) CancelCryptoOrder(orderID string) error {
-	endpoint := fmt.Sprintf("%s/orders/%s/cancel/", TradingURL, orderID)
+	return c.CancelCryptoOrderContext(context.Background(), orderID)
+}
+
+// CancelCryptoOrderContext is like CancelCryptoOrder but aborts when ctx is done
+func (c *CryptoClient) CancelCryptoOrderContext(ctx context.Context, orderID string) error {
+	endpoint := fmt.Sprintf("%s/orders/%s/cancel/", c.TradingURL, url.PathEscape(orderID))
 
-	resp, err := c.makeRequest("POST", endpoint, nil)
+	resp, err := c.makeRequest(ctx, "POST", endpoint, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return newAPIError(resp)
 	}
 
 	return nil
 }
 
-// GetTradingPairs retrieves list of supported trading pairs
-func (c *CryptoClient) GetTradingPairs(symbols []string) ([]map[string]interface{}, error) {
-	queryParams := ""
-	for i, symbol := range symbols {
-		if i == 0 {
-			queryParams = "?symbol=" + symbol
-		} else {
-			queryParams += "&symbol=" + symbol
-		}
-	}
+// cancelWorkers bounds how many cancel requests a bulk cancel keeps in
+// flight; the rate limiter still paces them
+const cancelWorkers = 4
 
-	endpoint := TradingURL + "/trading_pairs/" + queryParams
+// CancelResult is the outcome of canceling one order of a bulk cancel
+type CancelResult struct {
+	OrderID string
+	Err     error // nil when the order was canceled
+}
 
-	resp, err := c.makeRequest("GET", endpoint, nil)
-	if err != nil {
-		return nil, err
+// CancelCryptoOrdersContext cancels the given orders concurrently and
+// returns one result per order, in the order given. A failure only affects
+// its own order.
+func (c *CryptoClient) CancelCryptoOrdersContext(ctx context.Context, orderIDs []string) []CancelResult {
+	results := make([]CancelResult, len(orderIDs))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(cancelWorkers, len(orderIDs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = CancelResult{
+					OrderID: orderIDs[i],
+					Err:     c.CancelCryptoOrderContext(ctx, orderIDs[i]),
+				}
+			}
+		}()
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	for i := range orderIDs {
+		next <- i
 	}
+	close(next)
+	wg.Wait()
 
-	var response PaginatedResponse[map[string]interface{}]
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, err
-	}
+	return results
+}
 
-	return response.Results, nil
-}
\ No newline at end of file
+// GetOpenCryptoOrdersContext returns the orders matching filter that can
+// still be canceled, i.e. open and partially filled ones. filter.State is
+// ignored.
+func (c *CryptoClient) GetOpenCryptoOrdersContext(ctx context.Context, filter OrderFilter) ([]CryptoOrder, error) {
+	var open []CryptoOrder
+	for _, state := range []string{"open", "partially_filled"} {
+		filter.State = state
+		orders, err := c.CryptoOrdersPages(filter).AllContext(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		open = append(open, orders...)
+	}
+	return open, nil
+}
@@ -0,0 +1,452 @@
+package api
+
+import (
+	"bytes"
+	"dazedtrader/decimal"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// SchemaDrift describes a response field that did not have the shape the
+// client expects: a field it has never seen, or a value of the wrong type
+type SchemaDrift struct {
+	Payload string // Kind of object, e.g. "order" or "holding"
+	Field   string // Dotted path, e.g. "limit_order_config.limit_price"
+	Problem string // e.g. "unknown field" or "expected decimal, got object"
+	Value   string // The raw JSON value, shortened
+}
+
+// maxDriftValue bounds how much of an unexpected value is logged
+const maxDriftValue = 120
+
+// payload is one JSON object from the API being decoded into a typed struct.
+// Its accessors accept every shape the API is known to use for a field (text
+// or numbers for amounts and IDs, null for absent values) and record anything
+// else as drift. Fields that are never read are kept as raw JSON.
+type payload struct {
+	kind   string
+	path   string // Prefix of nested objects, e.g. "executions[0]."
+	fields map[string]json.RawMessage
+	read   map[string]bool
+	drift  *[]SchemaDrift // Shared with nested payloads
+}
+
+// newPayload parses data as a JSON object
+func newPayload(kind string, data []byte) (*payload, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", kind, err)
+	}
+	return &payload{
+		kind:   kind,
+		fields: fields,
+		read:   make(map[string]bool),
+		drift:  new([]SchemaDrift),
+	}, nil
+}
+
+// report records a value that does not match the expected shape
+func (p *payload) report(key, problem string, raw json.RawMessage) {
+	value := string(raw)
+	if len(value) > maxDriftValue {
+		value = value[:maxDriftValue] + "…"
+	}
+	*p.drift = append(*p.drift, SchemaDrift{
+		Payload: p.kind,
+		Field:   p.path + key,
+		Problem: problem,
+		Value:   value,
+	})
+}
+
+// raw returns the value of key and marks it read. Null reads as absent.
+func (p *payload) raw(key string) (json.RawMessage, bool) {
+	p.read[key] = true
+	value, ok := p.fields[key]
+	if !ok || string(value) == "null" {
+		return nil, false
+	}
+	return value, true
+}
+
+// jsonType names the type of a raw JSON value for drift reports
+func jsonType(raw json.RawMessage) string {
+	switch trimmed := bytes.TrimSpace(raw); {
+	case len(trimmed) == 0:
+		return "nothing"
+	case trimmed[0] == '"':
+		return "string"
+	case trimmed[0] == '{':
+		return "object"
+	case trimmed[0] == '[':
+		return "array"
+	case trimmed[0] == 't' || trimmed[0] == 'f':
+		return "boolean"
+	default:
+		return "number"
+	}
+}
+
+// string reads a text field. Numbers are accepted and kept as written.
+func (p *payload) string(key string) string {
+	raw, ok := p.raw(key)
+	if !ok {
+		return ""
+	}
+
+	switch jsonType(raw) {
+	case "string":
+		var value string
+		if err := json.Unmarshal(raw, &value); err == nil {
+			return value
+		}
+	case "number":
+		return string(bytes.TrimSpace(raw))
+	}
+	p.report(key, "expected string, got "+jsonType(raw), raw)
+	return ""
+}
+
+// decimal reads an amount sent either as a decimal string or a JSON number.
+// Numbers are parsed from their text, so no precision is lost on the way.
+func (p *payload) decimal(key string) decimal.Decimal {
+	raw, ok := p.raw(key)
+	if !ok {
+		return decimal.Zero
+	}
+
+	text := string(bytes.TrimSpace(raw))
+	switch jsonType(raw) {
+	case "string":
+		if err := json.Unmarshal(raw, &text); err != nil {
+			break
+		}
+		if text == "" {
+			return decimal.Zero
+		}
+		fallthrough
+	case "number":
+		if value, err := decimal.Parse(text); err == nil {
+			return value
+		}
+		p.report(key, "invalid decimal", raw)
+		return decimal.Zero
+	}
+	p.report(key, "expected decimal, got "+jsonType(raw), raw)
+	return decimal.Zero
+}
+
+// object reads a nested object, or returns nil when it is absent
+func (p *payload) object(key string) *payload {
+	raw, ok := p.raw(key)
+	if !ok {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if jsonType(raw) != "object" || json.Unmarshal(raw, &fields) != nil {
+		p.report(key, "expected object, got "+jsonType(raw), raw)
+		return nil
+	}
+	return p.nested(key+".", fields)
+}
+
+// objects reads an array of objects, skipping entries of any other type
+func (p *payload) objects(key string) []*payload {
+	raw, ok := p.raw(key)
+	if !ok {
+		return nil
+	}
+
+	var items []json.RawMessage
+	if jsonType(raw) != "array" || json.Unmarshal(raw, &items) != nil {
+		p.report(key, "expected array, got "+jsonType(raw), raw)
+		return nil
+	}
+
+	var nested []*payload
+	for i, item := range items {
+		itemKey := fmt.Sprintf("%s[%d]", key, i)
+		var fields map[string]json.RawMessage
+		if jsonType(item) != "object" || json.Unmarshal(item, &fields) != nil {
+			p.report(itemKey, "expected object, got "+jsonType(item), item)
+			continue
+		}
+		nested = append(nested, p.nested(itemKey+".", fields))
+	}
+	return nested
+}
+
+func (p *payload) nested(prefix string, fields map[string]json.RawMessage) *payload {
+	return &payload{
+		kind:   p.kind,
+		path:   p.path + prefix,
+		fields: fields,
+		read:   make(map[string]bool),
+		drift:  p.drift,
+	}
+}
+
+// extra returns the fields that were never read, reporting each as drift.
+// Call it after every known field has been decoded.
+func (p *payload) extra() map[string]json.RawMessage {
+	var unknown []string
+	for key := range p.fields {
+		if !p.read[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	extra := make(map[string]json.RawMessage, len(unknown))
+	for _, key := range unknown {
+		extra[key] = p.fields[key]
+		p.report(key, "unknown field", p.fields[key])
+	}
+	return extra
+}
+
+// decodeObject decodes a single object response, reporting any drift
+func decodeObject[T any](c *CryptoClient, kind string, body []byte, decode func(*payload) T) (T, error) {
+	p, err := newPayload(kind, body)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	value := decode(p)
+	c.reportDrift(*p.drift)
+	return value, nil
+}
+
+// pageDecoder returns a Paginator decode function that decodes each result
+// with decode and reports any drift
+func pageDecoder[T any](c *CryptoClient, kind string, decode func(*payload) T) func([]byte) ([]T, *string, error) {
+	return func(body []byte) ([]T, *string, error) {
+		var page struct {
+			Next    *string           `json:"next"`
+			Results []json.RawMessage `json:"results"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+
+		results := make([]T, 0, len(page.Results))
+		var drift []SchemaDrift
+		for _, result := range page.Results {
+			p, err := newPayload(kind, result)
+			if err != nil {
+				return nil, nil, err
+			}
+			results = append(results, decode(p))
+			drift = append(drift, *p.drift...)
+		}
+		c.reportDrift(drift)
+
+		if page.Next != nil && *page.Next == "" {
+			page.Next = nil
+		}
+		return results, page.Next, nil
+	}
+}
+
+// reportDrift logs each kind of drift once per client, so a changed field
+// doesn't flood the log on every refresh
+func (c *CryptoClient) reportDrift(drift []SchemaDrift) {
+	if len(drift) == 0 {
+		return
+	}
+
+	logger := c.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	c.driftMu.Lock()
+	defer c.driftMu.Unlock()
+	if c.driftSeen == nil {
+		c.driftSeen = make(map[string]bool)
+	}
+	for _, d := range drift {
+		// Array indexes would make every element a separate report
+		d.Field = stripIndexes(d.Field)
+		key := d.Payload + " " + d.Field + " " + d.Problem
+		if c.driftSeen[key] {
+			continue
+		}
+		c.driftSeen[key] = true
+		c.drift = append(c.drift, d)
+
+		logger.Warn("API response schema changed",
+			"payload", d.Payload,
+			"field", d.Field,
+			"problem", d.Problem,
+			"value", d.Value)
+	}
+}
+
+// stripIndexes turns "executions[3].price" into "executions[].price"
+func stripIndexes(field string) string {
+	var out strings.Builder
+	skipping := false
+	for _, r := range field {
+		switch {
+		case r == '[':
+			skipping = true
+			out.WriteRune(r)
+		case r == ']':
+			skipping = false
+			out.WriteRune(r)
+		case !skipping:
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+// SchemaDrift returns every distinct schema change seen in API responses so
+// far, oldest first
+func (c *CryptoClient) SchemaDrift() []SchemaDrift {
+	c.driftMu.Lock()
+	defer c.driftMu.Unlock()
+	return append([]SchemaDrift(nil), c.drift...)
+}
+
+// decodeOrder decodes an order. Amounts may be decimal strings or numbers.
+func decodeOrder(p *payload) CryptoOrder {
+	order := CryptoOrder{
+		ID:                  p.string("id"),
+		AccountNumber:       p.string("account_number"),
+		Symbol:              p.string("symbol"),
+		ClientOrderID:       p.string("client_order_id"),
+		Side:                p.string("side"),
+		Type:                p.string("type"),
+		State:               p.string("state"),
+		AveragePrice:        p.decimal("average_price"),
+		FilledAssetQuantity: p.decimal("filled_asset_quantity"),
+		CreatedAt:           p.string("created_at"),
+		UpdatedAt:           p.string("updated_at"),
+	}
+
+	for _, exec := range p.objects("executions") {
+		order.Executions = append(order.Executions, Execution{
+			EffectivePrice: exec.decimal("effective_price"),
+			Quantity:       exec.decimal("quantity"),
+			Timestamp:      exec.string("timestamp"),
+			Extra:          exec.extra(),
+		})
+	}
+
+	// Sum up the executions if filled_asset_quantity is not present
+	if order.FilledAssetQuantity.IsZero() {
+		for _, execution := range order.Executions {
+			order.FilledAssetQuantity = order.FilledAssetQuantity.Add(execution.Quantity)
+		}
+	}
+
+	// Read the ordered size from whichever order config is present
+	var limitPrice decimal.Decimal
+	for _, configKey := range []string{"market_order_config", "limit_order_config", "stop_loss_order_config", "stop_limit_order_config"} {
+		config := p.object(configKey)
+		if config == nil {
+			continue
+		}
+		order.Quantity = config.decimal("asset_quantity")
+		order.QuoteAmount = config.decimal("quote_amount")
+		if price := config.decimal("limit_price"); price.IsPositive() {
+			limitPrice = price
+		}
+		// Known fields the client has no use for yet
+		config.decimal("stop_price")
+		config.string("time_in_force")
+		config.extra()
+	}
+
+	// Use the limit price for average price if nothing has filled yet
+	if order.FilledAssetQuantity.IsZero() && order.AveragePrice.IsZero() {
+		order.AveragePrice = limitPrice
+	}
+
+	order.Extra = p.extra()
+	return order
+}
+
+// decodeAccount decodes a brokerage account
+func decodeAccount(p *payload) CryptoAccount {
+	account := CryptoAccount{
+		AccountNumber:       p.string("account_number"),
+		Status:              p.string("status"),
+		BuyingPower:         p.decimal("buying_power"),
+		BuyingPowerCurrency: p.string("buying_power_currency"),
+	}
+	account.Extra = p.extra()
+	return account
+}
+
+// decodeHolding decodes a holding
+func decodeHolding(p *payload) CryptoHolding {
+	holding := CryptoHolding{
+		AccountNumber:               p.string("account_number"),
+		AssetCode:                   p.string("asset_code"),
+		TotalQuantity:               p.decimal("total_quantity"),
+		QuantityAvailableForTrading: p.decimal("quantity_available_for_trading"),
+	}
+	holding.Extra = p.extra()
+	return holding
+}
+
+// decodeQuote decodes a best bid/ask quote
+func decodeQuote(p *payload) BestBidAsk {
+	quote := BestBidAsk{
+		Symbol:     p.string("symbol"),
+		Price:      p.decimal("price"),
+		BidPrice:   p.decimal("bid_inclusive_of_sell_spread"),
+		SellSpread: p.decimal("sell_spread"),
+		AskPrice:   p.decimal("ask_inclusive_of_buy_spread"),
+		BuySpread:  p.decimal("buy_spread"),
+		Timestamp:  p.string("timestamp"),
+	}
+	quote.Extra = p.extra()
+	return quote
+}
+
+// decodeEstimatedPrice decodes an execution quote
+func decodeEstimatedPrice(p *payload) EstimatedPrice {
+	estimate := EstimatedPrice{
+		Symbol:     p.string("symbol"),
+		Side:       p.string("side"),
+		Price:      p.decimal("price"),
+		Quantity:   p.decimal("quantity"),
+		BidPrice:   p.decimal("bid_inclusive_of_sell_spread"),
+		SellSpread: p.decimal("sell_spread"),
+		AskPrice:   p.decimal("ask_inclusive_of_buy_spread"),
+		BuySpread:  p.decimal("buy_spread"),
+		Timestamp:  p.string("timestamp"),
+	}
+	estimate.Extra = p.extra()
+	return estimate
+}
+
+// decodeTradingPair decodes a trading pair's order rules
+func decodeTradingPair(p *payload) TradingPair {
+	pair := TradingPair{
+		Symbol:         p.string("symbol"),
+		AssetCode:      p.string("asset_code"),
+		QuoteCode:      p.string("quote_code"),
+		AssetIncrement: p.decimal("asset_increment"),
+		QuoteIncrement: p.decimal("quote_increment"),
+		MinOrderSize:   p.decimal("min_order_size"),
+		MaxOrderSize:   p.decimal("max_order_size"),
+		Status:         p.string("status"),
+	}
+	if pair.AssetCode == "" {
+		pair.AssetCode = strings.TrimSuffix(pair.Symbol, "-USD")
+	}
+	pair.Extra = p.extra()
+	return pair
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the API answers with an unexpected status code.
+// Robinhood reports problems as {"type": "...", "errors": [{"detail": "...", "attr": "..."}]};
+// those fields are parsed when present and the raw body is kept otherwise.
+type APIError struct {
+	StatusCode int
+	Type       string
+	Errors     []ErrorDetail
+	Body       string
+}
+
+// ErrorDetail is a single entry of the API's "errors" list. Attr names the
+// offending request field and is empty for non-field errors.
+type ErrorDetail struct {
+	Detail string `json:"detail"`
+	Attr   string `json:"attr"`
+}
+
+// newAPIError builds an APIError from a failed response, consuming its body
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	}
+
+	var envelope struct {
+		Type   string        `json:"type"`
+		Errors []ErrorDetail `json:"errors"`
+		Detail string        `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil {
+		apiErr.Type = envelope.Type
+		apiErr.Errors = envelope.Errors
+		// Some endpoints answer with a bare {"detail": "..."}
+		if len(apiErr.Errors) == 0 && envelope.Detail != "" {
+			apiErr.Errors = []ErrorDetail{{Detail: envelope.Detail}}
+		}
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	if detail := e.Detail(); detail != "" {
+		return fmt.Sprintf("API error %d: %s", e.StatusCode, detail)
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+}
+
+// Detail joins the error details into one readable line, prefixing each
+// with the field it refers to
+func (e *APIError) Detail() string {
+	var parts []string
+	for _, item := range e.Errors {
+		if item.Attr != "" && item.Attr != "non_field_errors" {
+			parts = append(parts, item.Attr+": "+item.Detail)
+		} else {
+			parts = append(parts, item.Detail)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// SignError is returned when the signer could not sign a request, which
+// was therefore never sent
+type SignError struct {
+	Err error
+}
+
+func (e *SignError) Error() string {
+	return fmt.Sprintf("failed to sign request: %v", e.Err)
+}
+
+func (e *SignError) Unwrap() error {
+	return e.Err
+}
+
+// IsSignError reports whether err came from the signer rather than the API
+func IsSignError(err error) bool {
+	var signErr *SignError
+	return errors.As(err, &signErr)
+}
+
+// IsRateLimited reports whether err is an HTTP 429 from the API
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// IsAuthError reports whether the API rejected the credentials (401 or 403)
+func IsAuthError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// IsServerError reports whether the API failed with a 5xx status
+func IsServerError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 500
+}
+
+// IsRetryable reports whether the same request may succeed if sent again:
+// rate limits, server errors and network timeouts
+func IsRetryable(err error) bool {
+	if IsRateLimited(err) || IsServerError(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
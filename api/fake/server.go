@@ -0,0 +1,1045 @@
+// Package fake implements an in-process stand-in for the Robinhood Crypto
+// trading API. It checks request signatures the same way the real service
+// does and keeps accounts, holdings and orders in memory, so the client and
+// the TUI can run without network access or real credentials.
+package fake
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	tradingPath    = "/api/v1/crypto/trading"
+	marketDataPath = "/api/v1/crypto/marketdata"
+
+	// TimestampTolerance is how far x-timestamp may drift from the server clock
+	TimestampTolerance = 30 * time.Second
+
+	// Spread applied on each side of the mid price for quotes and fills
+	spreadRate = 0.0025
+
+	// Number of executions a marketable order fills in
+	fillSlices = 2
+
+	defaultPageSize = 100
+)
+
+// Server is a fake Robinhood Crypto API served over a local HTTP listener
+type Server struct {
+	// URL is the base URL to hand to CryptoClient.SetBaseURL
+	URL string
+
+	// FillDelay is how long a marketable order waits for each of its executions
+	FillDelay time.Duration
+
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	keys     map[string]ed25519.PublicKey
+	accounts []*account // the first is the default account
+	prices   map[string]float64
+	orders   []*order // newest first, across every account
+	now      func() time.Time
+}
+
+// account is one brokerage account with its own cash and holdings
+type account struct {
+	Number      string
+	BuyingPower float64
+	Holdings    map[string]*holding
+}
+
+type holding struct {
+	Total     float64
+	Available float64
+}
+
+type execution struct {
+	EffectivePrice float64 `json:"effective_price"`
+	Quantity       float64 `json:"quantity"`
+	Timestamp      string  `json:"timestamp"`
+}
+
+// tradingPair holds the order rules for a symbol
+type tradingPair struct {
+	AssetIncrement float64
+	QuoteIncrement float64
+	MinOrderSize   float64
+	MaxOrderSize   float64
+}
+
+type order struct {
+	ID            string
+	Account       *account
+	ClientOrderID string
+	Symbol        string
+	Side          string
+	Type          string
+	State         string
+	Quantity      float64
+	QuoteAmount   float64 // set when the order was sized in USD
+	LimitPrice    float64
+	StopPrice     float64
+	Triggered     bool // stop price has been reached
+	TimeInForce   string
+	Reserved      float64 // buying power held back while a buy is open
+	Executions    []execution
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewServer starts a fake API seeded with a funded account, a few holdings
+// and some order history. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		FillDelay: 2 * time.Second,
+		keys:      make(map[string]ed25519.PublicKey),
+		accounts: []*account{{
+			Number:      "FAKE0001",
+			BuyingPower: 10000,
+			Holdings: map[string]*holding{
+				"BTC":  {Total: 0.0512, Available: 0.0512},
+				"ETH":  {Total: 1.25, Available: 1.25},
+				"DOGE": {Total: 1500, Available: 1500},
+			},
+		}},
+		prices: map[string]float64{
+			"BTC-USD": 43250.50, "ETH-USD": 2642.30, "SOL-USD": 102.45,
+			"DOGE-USD": 0.0825, "ADA-USD": 0.485, "AVAX-USD": 38.90,
+			"DOT-USD": 6.82, "ALGO-USD": 0.195, "XLM-USD": 0.125,
+			"ATOM-USD": 10.45, "UNI-USD": 8.45, "COMP-USD": 65.20,
+			"LTC-USD": 72.45, "LINK-USD": 14.82, "BCH-USD": 245.60,
+			"MATIC-USD": 0.94, "SHIB-USD": 0.0000095, "XRP-USD": 0.615,
+			"TRX-USD": 0.105, "FIL-USD": 5.85, "ETC-USD": 22.50,
+			"EOS-USD": 0.825, "XTZ-USD": 1.05, "ZEC-USD": 28.40,
+			"CRV-USD": 0.845, "AAVE-USD": 98.50, "SUSHI-USD": 1.25,
+			"QTUM-USD": 2.95, "DASH-USD": 32.80, "NEO-USD": 12.40,
+		},
+		now: time.Now,
+	}
+	s.seedOrders()
+
+	s.srv = httptest.NewServer(http.HandlerFunc(s.ServeHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the listener down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// AddKey registers an API key and the public half of its signing key
+func (s *Server) AddKey(apiKey string, publicKey ed25519.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[apiKey] = publicKey
+}
+
+// NewCredentials generates a key pair, registers it and returns it in the
+// "apikey:privatekey" format accepted by api.NewCryptoClient
+func (s *Server) NewCredentials() (string, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate key pair: %w", err)
+	}
+
+	apiKey := "fake-" + uuid.New().String()
+	s.AddKey(apiKey, publicKey)
+
+	return apiKey + ":" + base64.StdEncoding.EncodeToString(privateKey), nil
+}
+
+// AddAccount opens another account, funded with buyingPower and holding
+// nothing, that every API key can trade in
+func (s *Server) AddAccount(number string, buyingPower float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts = append(s.accounts, &account{
+		Number:      number,
+		BuyingPower: buyingPower,
+		Holdings:    make(map[string]*holding),
+	})
+}
+
+// lookupAccount finds an account by number; an empty number means the
+// default account
+func (s *Server) lookupAccount(number string) *account {
+	if number == "" {
+		return s.accounts[0]
+	}
+	for _, a := range s.accounts {
+		if a.Number == number {
+			return a
+		}
+	}
+	return nil
+}
+
+// SetPrice sets the mid price quoted for a symbol such as "BTC-USD"
+func (s *Server) SetPrice(symbol string, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prices[symbol] = price
+}
+
+// SetClockOffset runs the server's clock offset from the local one, to
+// exercise the client's clock-skew correction
+func (s *Server) SetClockOffset(offset time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = func() time.Time {
+		return time.Now().Add(offset)
+	}
+}
+
+// seedOrders creates enough filled history to span more than one page
+func (s *Server) seedOrders() {
+	start := s.now().Add(-30 * 24 * time.Hour)
+	symbols := []string{"BTC-USD", "ETH-USD", "DOGE-USD"}
+	quantities := []float64{0.01, 0.25, 500}
+
+	for i := 0; i < 25; i++ {
+		symbol := symbols[i%len(symbols)]
+		side := "buy"
+		if i%4 == 3 {
+			side = "sell"
+		}
+		created := start.Add(time.Duration(i) * 26 * time.Hour)
+		price := s.prices[symbol] * (0.9 + float64(i%5)*0.03)
+
+		o := &order{
+			ID:            uuid.New().String(),
+			Account:       s.accounts[0],
+			ClientOrderID: uuid.New().String(),
+			Symbol:        symbol,
+			Side:          side,
+			Type:          "market",
+			State:         "filled",
+			Quantity:      quantities[i%len(quantities)],
+			TimeInForce:   "gtc",
+			CreatedAt:     created,
+			UpdatedAt:     created.Add(time.Second),
+		}
+		o.Executions = []execution{{
+			EffectivePrice: price,
+			Quantity:       o.Quantity,
+			Timestamp:      o.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		}}
+		s.orders = append([]*order{o}, s.orders...)
+	}
+}
+
+// ServeHTTP authenticates the request and routes it to the endpoint handlers
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "client_error", "", "could not read request body")
+		return
+	}
+
+	s.mu.Lock()
+	w.Header().Set("Date", s.now().UTC().Format(http.TimeFormat))
+	s.mu.Unlock()
+
+	if status, detail := s.authenticate(r, body); status != http.StatusOK {
+		writeError(w, status, "client_error", "", detail)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance()
+
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && path == tradingPath+"/accounts/":
+		s.handleAccounts(w, r)
+	case r.Method == http.MethodGet && path == tradingPath+"/holdings/":
+		s.handleHoldings(w, r)
+	case r.Method == http.MethodGet && path == tradingPath+"/trading_pairs/":
+		s.handleTradingPairs(w, r)
+	case r.Method == http.MethodGet && path == marketDataPath+"/best_bid_ask/":
+		s.handleBestBidAsk(w, r)
+	case r.Method == http.MethodGet && path == marketDataPath+"/estimated_price/":
+		s.handleEstimatedPrice(w, r)
+	case r.Method == http.MethodGet && path == tradingPath+"/orders/":
+		s.handleListOrders(w, r)
+	case r.Method == http.MethodPost && path == tradingPath+"/orders/":
+		s.handleCreateOrder(w, body)
+	case strings.HasPrefix(path, tradingPath+"/orders/"):
+		rest := strings.Trim(strings.TrimPrefix(path, tradingPath+"/orders/"), "/")
+		parts := strings.Split(rest, "/")
+		switch {
+		case r.Method == http.MethodGet && len(parts) == 1:
+			s.handleGetOrder(w, parts[0])
+		case r.Method == http.MethodPost && len(parts) == 2 && parts[1] == "cancel":
+			s.handleCancelOrder(w, parts[0])
+		default:
+			writeError(w, http.StatusNotFound, "client_error", "", "Not found.")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "client_error", "", "Not found.")
+	}
+}
+
+// authenticate verifies the x-api-key, x-timestamp and x-signature headers.
+// The signed message is api_key + timestamp + path + method + body.
+func (s *Server) authenticate(r *http.Request, body []byte) (int, string) {
+	apiKey := r.Header.Get("x-api-key")
+	timestamp := r.Header.Get("x-timestamp")
+	signature := r.Header.Get("x-signature")
+	if apiKey == "" || timestamp == "" || signature == "" {
+		return http.StatusUnauthorized, "Authentication credentials were not provided."
+	}
+
+	s.mu.Lock()
+	publicKey, ok := s.keys[apiKey]
+	now := s.now()
+	s.mu.Unlock()
+	if !ok {
+		return http.StatusUnauthorized, "Invalid API key."
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return http.StatusUnauthorized, "Invalid timestamp."
+	}
+	skew := now.Sub(time.Unix(seconds, 0))
+	if skew > TimestampTolerance || skew < -TimestampTolerance {
+		return http.StatusUnauthorized, "Timestamp is outside the allowed window."
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return http.StatusUnauthorized, "Invalid signature."
+	}
+
+	message := apiKey + timestamp + r.URL.RequestURI() + r.Method + string(body)
+	if !ed25519.Verify(publicKey, []byte(message), sig) {
+		return http.StatusForbidden, "Invalid signature."
+	}
+
+	return http.StatusOK, ""
+}
+
+// advance moves open orders forward: a marketable order fills in fillSlices
+// executions, one every FillDelay after it was placed
+func (s *Server) advance() {
+	now := s.now()
+	for _, o := range s.orders {
+		for o.State == "open" || o.State == "partially_filled" {
+			due := s.FillDelay * time.Duration(len(o.Executions)+1)
+			if now.Sub(o.CreatedAt) < due {
+				break
+			}
+
+			price, ok := s.fillPrice(o)
+			if !ok {
+				break
+			}
+
+			remaining := o.Quantity - o.filledQuantity()
+			quantity := o.Quantity / fillSlices
+			if len(o.Executions) == fillSlices-1 {
+				quantity = remaining
+			}
+
+			o.Executions = append(o.Executions, execution{
+				EffectivePrice: price,
+				Quantity:       quantity,
+				Timestamp:      o.CreatedAt.Add(due).UTC().Format(time.RFC3339Nano),
+			})
+			o.State = "partially_filled"
+			if quantity >= remaining {
+				o.State = "filled"
+			}
+			o.UpdatedAt = now
+			s.settle(o, quantity, remaining, price)
+		}
+	}
+}
+
+// filledQuantity sums the order's executions
+func (o *order) filledQuantity() float64 {
+	filled := 0.0
+	for _, e := range o.Executions {
+		filled += e.Quantity
+	}
+	return filled
+}
+
+// fillPrice returns the execution price if the order can fill right now
+func (s *Server) fillPrice(o *order) (float64, bool) {
+	mid, ok := s.prices[o.Symbol]
+	if !ok {
+		return 0, false
+	}
+
+	bid := mid * (1 - spreadRate)
+	ask := mid * (1 + spreadRate)
+
+	// Stop orders rest until the market trades through the stop price, then
+	// behave like a market (stop_loss) or limit (stop_limit) order
+	if o.Type == "stop_loss" || o.Type == "stop_limit" {
+		if !o.Triggered {
+			o.Triggered = (o.Side == "sell" && bid <= o.StopPrice) || (o.Side == "buy" && ask >= o.StopPrice)
+		}
+		if !o.Triggered {
+			return 0, false
+		}
+	}
+
+	switch o.Type {
+	case "market", "stop_loss":
+		if o.Side == "buy" {
+			return ask, true
+		}
+		return bid, true
+	case "limit", "stop_limit":
+		if o.Side == "buy" && ask <= o.LimitPrice {
+			return ask, true
+		}
+		if o.Side == "sell" && bid >= o.LimitPrice {
+			return bid, true
+		}
+	}
+	return 0, false
+}
+
+// settle applies a fill of quantity, out of the remaining unfilled quantity,
+// to buying power and holdings
+func (s *Server) settle(o *order, quantity, remaining, price float64) {
+	asset := strings.TrimSuffix(o.Symbol, "-USD")
+	h := o.Account.Holdings[asset]
+	if h == nil {
+		h = &holding{}
+		o.Account.Holdings[asset] = h
+	}
+
+	if o.Side == "buy" {
+		// Buying power was reserved at the limit/estimated price; release this
+		// fill's share and refund the difference
+		released := o.Reserved * quantity / remaining
+		o.Reserved -= released
+		o.Account.BuyingPower += released - quantity*price
+		h.Total += quantity
+		h.Available += quantity
+	} else {
+		h.Total -= quantity
+		o.Account.BuyingPower += quantity * price
+	}
+}
+
+// referencePrice is the price an order is expected to execute at
+func (s *Server) referencePrice(o *order) float64 {
+	switch o.Type {
+	case "limit", "stop_limit":
+		return o.LimitPrice
+	case "stop_loss":
+		return o.StopPrice
+	}
+	if o.Side == "buy" {
+		return s.prices[o.Symbol] * (1 + spreadRate)
+	}
+	return s.prices[o.Symbol] * (1 - spreadRate)
+}
+
+// reservedCost is the buying power held back while a buy order is open
+func (s *Server) reservedCost(o *order) float64 {
+	switch o.Type {
+	case "limit", "stop_limit":
+		return o.Quantity * o.LimitPrice
+	case "stop_loss":
+		return o.Quantity * math.Max(o.StopPrice, s.prices[o.Symbol]) * (1 + spreadRate)
+	}
+	return o.Quantity * s.prices[o.Symbol] * (1 + spreadRate)
+}
+
+// handleAccounts serves the lone account as a bare object, the way the API
+// answers keys with one account, and a page of accounts otherwise
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	if len(s.accounts) == 1 {
+		writeJSON(w, http.StatusOK, accountJSON(s.accounts[0]))
+		return
+	}
+
+	results := make([]interface{}, 0, len(s.accounts))
+	for _, a := range s.accounts {
+		results = append(results, accountJSON(a))
+	}
+	s.writePage(w, r, results)
+}
+
+func accountJSON(a *account) map[string]interface{} {
+	return map[string]interface{}{
+		"account_number":        a.Number,
+		"status":                "active",
+		"buying_power":          strconv.FormatFloat(a.BuyingPower, 'f', 2, 64),
+		"buying_power_currency": "USD",
+	}
+}
+
+func (s *Server) handleHoldings(w http.ResponseWriter, r *http.Request) {
+	acct := s.lookupAccount(r.URL.Query().Get("account_number"))
+	if acct == nil {
+		writeError(w, http.StatusBadRequest, "validation_error", "account_number", "Account not found.")
+		return
+	}
+
+	assets := make([]string, 0, len(acct.Holdings))
+	for asset, h := range acct.Holdings {
+		if h.Total > 0 {
+			assets = append(assets, asset)
+		}
+	}
+	sort.Strings(assets)
+
+	results := make([]interface{}, 0, len(assets))
+	for _, asset := range assets {
+		h := acct.Holdings[asset]
+		results = append(results, map[string]interface{}{
+			"account_number":                 acct.Number,
+			"asset_code":                     asset,
+			"total_quantity":                 strconv.FormatFloat(h.Total, 'f', -1, 64),
+			"quantity_available_for_trading": strconv.FormatFloat(h.Available, 'f', -1, 64),
+		})
+	}
+
+	s.writePage(w, r, results)
+}
+
+// pairRules derives order rules from a pair's price so that cheap coins
+// trade in whole units and every pair has a minimum order of about a dollar
+func pairRules(price float64) tradingPair {
+	p := tradingPair{AssetIncrement: 1, QuoteIncrement: 0.00000001}
+	switch {
+	case price >= 1000:
+		p.AssetIncrement = 0.00000001
+	case price >= 1:
+		p.AssetIncrement = 0.000001
+	case price >= 0.01:
+		p.AssetIncrement = 0.01
+	}
+	switch {
+	case price >= 1:
+		p.QuoteIncrement = 0.01
+	case price >= 0.01:
+		p.QuoteIncrement = 0.000001
+	}
+	p.MinOrderSize = math.Max(math.Pow(10, math.Floor(math.Log10(1/price))), p.AssetIncrement)
+	p.MaxOrderSize = math.Pow(10, math.Floor(math.Log10(1000000/price)))
+	return p
+}
+
+func (s *Server) handleTradingPairs(w http.ResponseWriter, r *http.Request) {
+	symbols := r.URL.Query()["symbol"]
+	if len(symbols) == 0 {
+		for symbol := range s.prices {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+	}
+
+	results := make([]interface{}, 0, len(symbols))
+	for _, symbol := range symbols {
+		price, ok := s.prices[symbol]
+		if !ok {
+			continue
+		}
+		p := pairRules(price)
+		results = append(results, map[string]interface{}{
+			"symbol":          symbol,
+			"asset_code":      strings.TrimSuffix(symbol, "-USD"),
+			"quote_code":      "USD",
+			"asset_increment": fmt.Sprintf("%.18f", p.AssetIncrement),
+			"quote_increment": fmt.Sprintf("%.18f", p.QuoteIncrement),
+			"min_order_size":  fmt.Sprintf("%.18f", p.MinOrderSize),
+			"max_order_size":  fmt.Sprintf("%.18f", p.MaxOrderSize),
+			"status":          "tradable",
+		})
+	}
+	s.writePage(w, r, results)
+}
+
+// isMultiple reports whether value is a whole number of increments
+func isMultiple(value, increment float64) bool {
+	steps := value / increment
+	return math.Abs(steps-math.Round(steps)) < 1e-6
+}
+
+func (s *Server) handleBestBidAsk(w http.ResponseWriter, r *http.Request) {
+	symbols := r.URL.Query()["symbol"]
+	if len(symbols) == 0 {
+		for symbol := range s.prices {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+	}
+
+	// Like the real API, one unknown symbol fails the whole query
+	for _, symbol := range symbols {
+		if _, ok := s.prices[symbol]; !ok {
+			writeError(w, http.StatusBadRequest, "validation_error", "symbol", "Invalid symbol: "+symbol)
+			return
+		}
+	}
+
+	now := s.now().UTC().Format(time.RFC3339Nano)
+	results := []interface{}{}
+	for _, symbol := range symbols {
+		mid := s.prices[symbol]
+		results = append(results, map[string]interface{}{
+			"symbol":                       symbol,
+			"price":                        mid,
+			"bid_inclusive_of_sell_spread": mid * (1 - spreadRate),
+			"sell_spread":                  spreadRate,
+			"ask_inclusive_of_buy_spread":  mid * (1 + spreadRate),
+			"buy_spread":                   spreadRate,
+			"timestamp":                    now,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
+// handleEstimatedPrice quotes each requested quantity. The spread widens
+// with the order's notional value, roughly like a real order book.
+func (s *Server) handleEstimatedPrice(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	symbol := query.Get("symbol")
+	side := query.Get("side")
+
+	mid, ok := s.prices[symbol]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "validation_error", "symbol", "Invalid symbol: "+symbol)
+		return
+	}
+	if side != "bid" && side != "ask" && side != "both" {
+		writeError(w, http.StatusBadRequest, "validation_error", "side", "Must be one of bid, ask or both.")
+		return
+	}
+
+	now := s.now().UTC().Format(time.RFC3339Nano)
+	results := []interface{}{}
+	for _, raw := range strings.Split(query.Get("quantity"), ",") {
+		quantity, err := strconv.ParseFloat(raw, 64)
+		if err != nil || quantity <= 0 {
+			writeError(w, http.StatusBadRequest, "validation_error", "quantity", "A valid positive number is required.")
+			return
+		}
+
+		spread := spreadRate * (1 + quantity*mid/1000000)
+		results = append(results, map[string]interface{}{
+			"symbol":                       symbol,
+			"side":                         side,
+			"price":                        mid,
+			"quantity":                     quantity,
+			"bid_inclusive_of_sell_spread": mid * (1 - spread),
+			"sell_spread":                  spread,
+			"ask_inclusive_of_buy_spread":  mid * (1 + spread),
+			"buy_spread":                   spread,
+			"timestamp":                    now,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
+func (s *Server) handleListOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	// Time range filters, each bound inclusive
+	ranges := []struct {
+		key   string
+		after bool
+		field func(*order) time.Time
+	}{
+		{"created_at_start", true, func(o *order) time.Time { return o.CreatedAt }},
+		{"created_at_end", false, func(o *order) time.Time { return o.CreatedAt }},
+		{"updated_at_start", true, func(o *order) time.Time { return o.UpdatedAt }},
+		{"updated_at_end", false, func(o *order) time.Time { return o.UpdatedAt }},
+	}
+	bounds := make(map[string]time.Time)
+	for _, rng := range ranges {
+		if raw := query.Get(rng.key); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "validation_error", rng.key, "Enter a valid date/time.")
+				return
+			}
+			bounds[rng.key] = t
+		}
+	}
+
+	results := make([]interface{}, 0, len(s.orders))
+	for _, o := range s.orders {
+		if !matches(query.Get("account_number"), o.Account.Number) || !matches(query.Get("symbol"), o.Symbol) || !matches(query.Get("side"), o.Side) ||
+			!matches(query.Get("state"), o.State) || !matches(query.Get("type"), o.Type) {
+			continue
+		}
+
+		inRange := true
+		for _, rng := range ranges {
+			bound, ok := bounds[rng.key]
+			if !ok {
+				continue
+			}
+			t := rng.field(o)
+			if (rng.after && t.Before(bound)) || (!rng.after && t.After(bound)) {
+				inRange = false
+			}
+		}
+		if inRange {
+			results = append(results, s.orderJSON(o))
+		}
+	}
+	s.writePage(w, r, results)
+}
+
+// matches reports whether value passes an optional equality filter
+func matches(filter, value string) bool {
+	return filter == "" || filter == value
+}
+
+func (s *Server) handleGetOrder(w http.ResponseWriter, id string) {
+	o := s.findOrder(id)
+	if o == nil {
+		writeError(w, http.StatusNotFound, "client_error", "", "Not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.orderJSON(o))
+}
+
+func (s *Server) handleCreateOrder(w http.ResponseWriter, body []byte) {
+	var req struct {
+		AccountNumber        string                 `json:"account_number"`
+		ClientOrderID        string                 `json:"client_order_id"`
+		Side                 string                 `json:"side"`
+		Type                 string                 `json:"type"`
+		Symbol               string                 `json:"symbol"`
+		MarketOrderConfig    map[string]interface{} `json:"market_order_config"`
+		LimitOrderConfig     map[string]interface{} `json:"limit_order_config"`
+		StopLossOrderConfig  map[string]interface{} `json:"stop_loss_order_config"`
+		StopLimitOrderConfig map[string]interface{} `json:"stop_limit_order_config"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", "non_field_errors", "Invalid JSON body.")
+		return
+	}
+
+	acct := s.lookupAccount(req.AccountNumber)
+	if acct == nil {
+		writeError(w, http.StatusBadRequest, "validation_error", "account_number", "Account not found.")
+		return
+	}
+	if req.ClientOrderID == "" {
+		writeError(w, http.StatusBadRequest, "validation_error", "client_order_id", "This field is required.")
+		return
+	}
+	for _, o := range s.orders {
+		if o.ClientOrderID == req.ClientOrderID {
+			writeError(w, http.StatusBadRequest, "validation_error", "client_order_id", "An order with this client_order_id already exists.")
+			return
+		}
+	}
+	if req.Side != "buy" && req.Side != "sell" {
+		writeError(w, http.StatusBadRequest, "validation_error", "side", fmt.Sprintf("\"%s\" is not a valid choice.", req.Side))
+		return
+	}
+	if _, ok := s.prices[req.Symbol]; !ok {
+		writeError(w, http.StatusBadRequest, "validation_error", "symbol", fmt.Sprintf("Trading pair %s is not supported.", req.Symbol))
+		return
+	}
+
+	o := &order{
+		ID:            uuid.New().String(),
+		Account:       acct,
+		ClientOrderID: req.ClientOrderID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		State:         "open",
+		TimeInForce:   "gtc",
+		CreatedAt:     s.now(),
+		UpdatedAt:     s.now(),
+	}
+
+	var config map[string]interface{}
+	switch req.Type {
+	case "market":
+		config = req.MarketOrderConfig
+	case "limit":
+		config = req.LimitOrderConfig
+	case "stop_loss":
+		config = req.StopLossOrderConfig
+	case "stop_limit":
+		config = req.StopLimitOrderConfig
+	default:
+		writeError(w, http.StatusBadRequest, "validation_error", "type", fmt.Sprintf("\"%s\" is not a valid choice.", req.Type))
+		return
+	}
+	if config == nil {
+		writeError(w, http.StatusBadRequest, "validation_error", req.Type+"_order_config", "This field is required.")
+		return
+	}
+
+	var ok bool
+	_, hasQuantity := config["asset_quantity"]
+	_, hasAmount := config["quote_amount"]
+	if hasQuantity == hasAmount {
+		writeError(w, http.StatusBadRequest, "validation_error", "non_field_errors", "Exactly one of asset_quantity or quote_amount is required.")
+		return
+	}
+	if hasAmount {
+		if o.QuoteAmount, ok = numberField(config, "quote_amount"); !ok || o.QuoteAmount <= 0 {
+			writeError(w, http.StatusBadRequest, "validation_error", "quote_amount", "A positive quote_amount is required.")
+			return
+		}
+	} else if o.Quantity, ok = numberField(config, "asset_quantity"); !ok || o.Quantity <= 0 {
+		writeError(w, http.StatusBadRequest, "validation_error", "asset_quantity", "A positive asset_quantity is required.")
+		return
+	}
+	if req.Type == "limit" || req.Type == "stop_limit" {
+		if o.LimitPrice, ok = numberField(config, "limit_price"); !ok || o.LimitPrice <= 0 {
+			writeError(w, http.StatusBadRequest, "validation_error", "limit_price", "A positive limit_price is required.")
+			return
+		}
+	}
+	if req.Type == "stop_loss" || req.Type == "stop_limit" {
+		if o.StopPrice, ok = numberField(config, "stop_price"); !ok || o.StopPrice <= 0 {
+			writeError(w, http.StatusBadRequest, "validation_error", "stop_price", "A positive stop_price is required.")
+			return
+		}
+	}
+	if req.Type != "market" {
+		if tif, ok := config["time_in_force"].(string); ok && tif != "" {
+			if tif != "gtc" && tif != "gfd" {
+				writeError(w, http.StatusBadRequest, "validation_error", "time_in_force", fmt.Sprintf("\"%s\" is not a valid choice.", tif))
+				return
+			}
+			o.TimeInForce = tif
+		}
+	}
+
+	// Enforce the pair's increments and size limits like the real API
+	rules := pairRules(s.prices[req.Symbol])
+	if o.Quantity > 0 && !isMultiple(o.Quantity, rules.AssetIncrement) {
+		writeError(w, http.StatusBadRequest, "validation_error", "asset_quantity",
+			fmt.Sprintf("Ensure asset_quantity is a multiple of %g.", rules.AssetIncrement))
+		return
+	}
+	if o.QuoteAmount > 0 && !isMultiple(o.QuoteAmount, 0.01) {
+		writeError(w, http.StatusBadRequest, "validation_error", "quote_amount", "Ensure quote_amount is a multiple of 0.01.")
+		return
+	}
+	for attr, price := range map[string]float64{"limit_price": o.LimitPrice, "stop_price": o.StopPrice} {
+		if price > 0 && !isMultiple(price, rules.QuoteIncrement) {
+			writeError(w, http.StatusBadRequest, "validation_error", attr,
+				fmt.Sprintf("Ensure %s is a multiple of %g.", attr, rules.QuoteIncrement))
+			return
+		}
+	}
+
+	// Size USD orders at the price they are expected to fill at
+	if o.QuoteAmount > 0 {
+		o.Quantity = o.QuoteAmount / s.referencePrice(o)
+	}
+
+	if o.Quantity < rules.MinOrderSize || o.Quantity > rules.MaxOrderSize {
+		writeError(w, http.StatusBadRequest, "validation_error", "asset_quantity",
+			fmt.Sprintf("Order size must be between %g and %g.", rules.MinOrderSize, rules.MaxOrderSize))
+		return
+	}
+
+	asset := strings.TrimSuffix(req.Symbol, "-USD")
+	if req.Side == "buy" {
+		o.Reserved = s.reservedCost(o)
+		if o.Reserved > acct.BuyingPower {
+			writeError(w, http.StatusBadRequest, "validation_error", "non_field_errors", "Insufficient buying power.")
+			return
+		}
+		acct.BuyingPower -= o.Reserved
+	} else {
+		h := acct.Holdings[asset]
+		if h == nil || h.Available < o.Quantity {
+			writeError(w, http.StatusBadRequest, "validation_error", "non_field_errors", "Insufficient holdings.")
+			return
+		}
+		h.Available -= o.Quantity
+	}
+
+	s.orders = append([]*order{o}, s.orders...)
+	writeJSON(w, http.StatusCreated, s.orderJSON(o))
+}
+
+func (s *Server) handleCancelOrder(w http.ResponseWriter, id string) {
+	o := s.findOrder(id)
+	if o == nil {
+		writeError(w, http.StatusNotFound, "client_error", "", "Not found.")
+		return
+	}
+	if o.State != "open" && o.State != "partially_filled" {
+		writeError(w, http.StatusBadRequest, "validation_error", "non_field_errors", fmt.Sprintf("Order is %s and can no longer be canceled.", o.State))
+		return
+	}
+
+	// Release whatever the unfilled part of the order was holding back
+	if o.Side == "buy" {
+		o.Account.BuyingPower += o.Reserved
+		o.Reserved = 0
+	} else if h := o.Account.Holdings[strings.TrimSuffix(o.Symbol, "-USD")]; h != nil {
+		h.Available += o.Quantity - o.filledQuantity()
+	}
+
+	o.State = "canceled"
+	o.UpdatedAt = s.now()
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) findOrder(id string) *order {
+	for _, o := range s.orders {
+		if o.ID == id {
+			return o
+		}
+	}
+	return nil
+}
+
+func (s *Server) orderJSON(o *order) map[string]interface{} {
+	filled := 0.0
+	cost := 0.0
+	for _, e := range o.Executions {
+		filled += e.Quantity
+		cost += e.Quantity * e.EffectivePrice
+	}
+
+	var averagePrice interface{}
+	if filled > 0 {
+		averagePrice = cost / filled
+	}
+
+	executions := o.Executions
+	if executions == nil {
+		executions = []execution{}
+	}
+
+	result := map[string]interface{}{
+		"id":                    o.ID,
+		"account_number":        o.Account.Number,
+		"symbol":                o.Symbol,
+		"client_order_id":       o.ClientOrderID,
+		"side":                  o.Side,
+		"type":                  o.Type,
+		"state":                 o.State,
+		"average_price":         averagePrice,
+		"filled_asset_quantity": filled,
+		"executions":            executions,
+		"created_at":            o.CreatedAt.UTC().Format(time.RFC3339Nano),
+		"updated_at":            o.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+
+	config := map[string]interface{}{}
+	if o.QuoteAmount > 0 {
+		config["quote_amount"] = o.QuoteAmount
+	} else {
+		config["asset_quantity"] = o.Quantity
+	}
+	if o.Type != "market" {
+		config["time_in_force"] = o.TimeInForce
+	}
+	if o.Type == "limit" || o.Type == "stop_limit" {
+		config["limit_price"] = o.LimitPrice
+	}
+	if o.Type == "stop_loss" || o.Type == "stop_limit" {
+		config["stop_price"] = o.StopPrice
+	}
+	result[o.Type+"_order_config"] = config
+
+	return result
+}
+
+// writePage serves results using the same cursor pagination as the real API
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, results []interface{}) {
+	query := r.URL.Query()
+
+	limit := defaultPageSize
+	if val, err := strconv.Atoi(query.Get("limit")); err == nil && val > 0 {
+		limit = val
+	}
+	offset := 0
+	if val, err := strconv.Atoi(query.Get("cursor")); err == nil && val > 0 {
+		offset = val
+	}
+	if offset > len(results) {
+		offset = len(results)
+	}
+	end := offset + limit
+	if end > len(results) {
+		end = len(results)
+	}
+
+	var next, previous interface{}
+	if end < len(results) {
+		query.Set("cursor", strconv.Itoa(end))
+		next = s.URL + r.URL.Path + "?" + query.Encode()
+	}
+	if offset > 0 {
+		query.Set("cursor", strconv.Itoa(max(offset-limit, 0)))
+		previous = s.URL + r.URL.Path + "?" + query.Encode()
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"next":     next,
+		"previous": previous,
+		"results":  results[offset:end],
+	})
+}
+
+// numberField reads a value that may be sent either as a JSON number or a string
+func numberField(m map[string]interface{}, key string) (float64, bool) {
+	switch val := m[key].(type) {
+	case float64:
+		return val, true
+	case string:
+		parsed, err := strconv.ParseFloat(val, 64)
+		return parsed, err == nil
+	}
+	return 0, false
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError mirrors the API's error envelope:
+// {"type": "...", "errors": [{"detail": "...", "attr": "..."}]}
+func writeError(w http.ResponseWriter, status int, errType, attr, detail string) {
+	var attrValue interface{}
+	if attr != "" {
+		attrValue = attr
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"type": errType,
+		"errors": []map[string]interface{}{
+			{"detail": detail, "attr": attrValue},
+		},
+	})
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Paginator walks a paginated list endpoint one page at a time by following
+// the API's "next" cursor links. Every page request is signed by makeRequest.
+type Paginator[T any] struct {
+	client *CryptoClient
+	next   string
+	seen   map[string]bool // pages already read, to stop on a cursor loop
+	decode func([]byte) ([]T, *string, error)
+}
+
+func newPaginator[T any](c *CryptoClient, endpoint string, decode func([]byte) ([]T, *string, error)) *Paginator[T] {
+	return &Paginator[T]{
+		client: c,
+		next:   endpoint,
+		seen:   make(map[string]bool),
+		decode: decode,
+	}
+}
+
+// CryptoOrdersPages returns a paginator over the orders matching filter,
+// newest first. A positive filter.Limit sets the page size. Only orders of
+// the client's AccountNumber are listed when it is set and the filter names
+// no other account.
+func (c *CryptoClient) CryptoOrdersPages(filter OrderFilter) *Paginator[CryptoOrder] {
+	if filter.AccountNumber == "" {
+		filter.AccountNumber = c.AccountNumber
+	}
+	query := filter.query()
+	endpoint := c.TradingURL + "/orders/"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return newPaginator(c, endpoint, pageDecoder(c, "order", decodeOrder))
+}
+
+// CryptoHoldingsPages returns a paginator over the holdings of the client's
+// AccountNumber, or of the default account when it is empty
+func (c *CryptoClient) CryptoHoldingsPages() *Paginator[CryptoHolding] {
+	endpoint := c.TradingURL + "/holdings/"
+	if c.AccountNumber != "" {
+		endpoint += "?" + url.Values{"account_number": {c.AccountNumber}}.Encode()
+	}
+	return newPaginator(c, endpoint, pageDecoder(c, "holding", decodeHolding))
+}
+
+// CryptoAccountsPages returns a paginator over the accounts the API key can
+// trade. An API key with a single account gets that account back as a bare
+// object rather than a list; both shapes are accepted.
+func (c *CryptoClient) CryptoAccountsPages() *Paginator[CryptoAccount] {
+	decodePage := pageDecoder(c, "account", decodeAccount)
+	return newPaginator(c, c.TradingURL+"/accounts/", func(body []byte) ([]CryptoAccount, *string, error) {
+		var probe map[string]json.RawMessage
+		if err := json.Unmarshal(body, &probe); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+		if _, ok := probe["results"]; ok {
+			return decodePage(body)
+		}
+		account, err := decodeObject(c, "account", body, decodeAccount)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []CryptoAccount{account}, nil, nil
+	})
+}
+
+// HasNext reports whether another page can be fetched
+func (p *Paginator[T]) HasNext() bool {
+	return p.next != ""
+}
+
+// Next fetches the next page. It returns nil once every page has been read.
+func (p *Paginator[T]) Next() ([]T, error) {
+	return p.NextContext(context.Background())
+}
+
+// NextContext is like Next but aborts when ctx is done
+func (p *Paginator[T]) NextContext(ctx context.Context) ([]T, error) {
+	if p.next == "" {
+		return nil, nil
+	}
+
+	endpoint := p.next
+	// Cursor links are normally absolute, but accept a bare path as well
+	if strings.HasPrefix(endpoint, "/") {
+		endpoint = p.client.BaseURL + endpoint
+	}
+	if err := p.client.checkSameHost(endpoint); err != nil {
+		return nil, err
+	}
+	if p.seen[endpoint] {
+		return nil, fmt.Errorf("next page link %s points back to a page already read", endpoint)
+	}
+
+	resp, err := p.client.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	results, next, err := p.decode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	p.seen[endpoint] = true
+	p.next = ""
+	if next != nil {
+		p.next = *next
+	}
+
+	return results, nil
+}
+
+// checkSameHost refuses cursor links that point away from the API host,
+// since following one would send the signed headers, API key included, to
+// whoever served the link
+func (c *CryptoClient) checkSameHost(endpoint string) error {
+	link, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid next page link: %v", err)
+	}
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %v", err)
+	}
+	if !strings.EqualFold(link.Scheme, base.Scheme) || !strings.EqualFold(link.Host, base.Host) {
+		return fmt.Errorf("refusing to follow next page link to %s://%s, which is not the API host", link.Scheme, link.Host)
+	}
+	return nil
+}
+
+// All keeps fetching pages until the endpoint is exhausted or maxResults
+// items have been collected. A maxResults of 0 means no limit.
+func (p *Paginator[T]) All(maxResults int) ([]T, error) {
+	return p.AllContext(context.Background(), maxResults)
+}
+
+// AllContext is like All but aborts when ctx is done
+func (p *Paginator[T]) AllContext(ctx context.Context, maxResults int) ([]T, error) {
+	var all []T
+	for p.HasNext() {
+		page, err := p.NextContext(ctx)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, page...)
+
+		if maxResults > 0 && len(all) >= maxResults {
+			return all[:maxResults], nil
+		}
+	}
+	return all, nil
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// Signer produces the Ed25519 signature Robinhood expects on each request.
+// Implementations in package api/signer keep the private key encrypted or
+// in another process.
+type Signer interface {
+	// Sign returns the signature of message, or an error when the key is
+	// unavailable
+	Sign(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// KeySigner signs with a private key held in memory
+type KeySigner struct {
+	key ed25519.PrivateKey
+}
+
+// NewKeySigner returns a signer for key
+func NewKeySigner(key ed25519.PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+// Sign signs message with the key
+func (s *KeySigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, message), nil
+}
+
+// GenerateKey creates a key pair for a new API key. The public key is
+// returned base64 encoded, as Robinhood asks for it when the API key is
+// created.
+func GenerateKey() (string, ed25519.PrivateKey, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate key pair: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(publicKey), privateKey, nil
+}
+
+// FormatCredentials joins apiKey and its private key in the
+// "apikey:privatekey" format read by ParseCredentials
+func FormatCredentials(apiKey string, privateKey ed25519.PrivateKey) string {
+	return apiKey + ":" + base64.StdEncoding.EncodeToString(privateKey)
+}
@@ -0,0 +1,210 @@
+package signer
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"sync"
+)
+
+// The external signer protocol is one JSON object per line in each
+// direction. Every request carries an ID that its response echoes, so
+// requests may be answered out of order:
+//
+//	→ {"id": 1, "method": "sign", "message": "<base64>"}
+//	← {"id": 1, "signature": "<base64>"}
+//	→ {"id": 2, "method": "api_key"}
+//	← {"id": 2, "api_key": "..."}
+//
+// A request that fails is answered with {"id": n, "error": "..."}.
+type request struct {
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Message []byte `json:"message,omitempty"`
+}
+
+type response struct {
+	ID        uint64 `json:"id"`
+	Signature []byte `json:"signature,omitempty"`
+	APIKey    string `json:"api_key,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// maxLine bounds a single protocol message
+const maxLine = 1024 * 1024
+
+// External signs requests through a signer running in another process, so
+// the private key never enters this one
+type External struct {
+	writeMu sync.Mutex
+	w       io.Writer
+	close   func() error
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan response
+	err     error // set once the connection is gone
+}
+
+// StartProcess runs name with args as a signer speaking the protocol on its
+// stdin and stdout. Its stderr is discarded, since it would garble the TUI.
+func StartProcess(name string, args ...string) (*External, error) {
+	cmd := exec.Command(name, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start signer: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start signer: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start signer: %w", err)
+	}
+
+	return newExternal(stdout, stdin, func() error {
+		stdin.Close()
+		return cmd.Wait()
+	}), nil
+}
+
+// Dial connects to a signer listening on a Unix socket
+func Dial(socketPath string) (*External, error) {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to signer: %w", err)
+	}
+	return newExternal(conn, conn, conn.Close), nil
+}
+
+func newExternal(r io.Reader, w io.Writer, close func() error) *External {
+	s := &External{
+		w:       w,
+		close:   close,
+		pending: make(map[uint64]chan response),
+	}
+	go s.read(r)
+	return s
+}
+
+// read hands each response to the request waiting for it until the
+// connection ends, then fails every request still waiting
+func (s *External) read(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxLine)
+
+	var err error
+	for scanner.Scan() {
+		var resp response
+		if err = json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			err = fmt.Errorf("invalid signer response: %w", err)
+			break
+		}
+
+		s.mu.Lock()
+		if ch, ok := s.pending[resp.ID]; ok {
+			delete(s.pending, resp.ID)
+			ch <- resp
+		}
+		s.mu.Unlock()
+	}
+	if err == nil {
+		err = scanner.Err()
+	}
+	if err == nil {
+		err = io.EOF
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = fmt.Errorf("signer connection closed: %w", err)
+	for id, ch := range s.pending {
+		delete(s.pending, id)
+		close(ch)
+	}
+}
+
+// call sends req and waits for its response
+func (s *External) call(ctx context.Context, req request) (response, error) {
+	ch := make(chan response, 1)
+
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return response{}, s.err
+	}
+	s.nextID++
+	req.ID = s.nextID
+	s.pending[req.ID] = ch
+	s.mu.Unlock()
+
+	forget := func() {
+		s.mu.Lock()
+		delete(s.pending, req.ID)
+		s.mu.Unlock()
+	}
+
+	line, err := json.Marshal(req)
+	if err != nil {
+		forget()
+		return response{}, err
+	}
+	s.writeMu.Lock()
+	_, err = s.w.Write(append(line, '\n'))
+	s.writeMu.Unlock()
+	if err != nil {
+		forget()
+		return response{}, fmt.Errorf("failed to reach signer: %w", err)
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return response{}, s.err
+		}
+		if resp.Error != "" {
+			return response{}, errors.New("signer: " + resp.Error)
+		}
+		return resp, nil
+	case <-ctx.Done():
+		forget()
+		return response{}, ctx.Err()
+	}
+}
+
+// Sign asks the signer to sign message
+func (s *External) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	resp, err := s.call(ctx, request{Method: "sign", Message: message})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("signer returned a %d-byte signature", len(resp.Signature))
+	}
+	return resp.Signature, nil
+}
+
+// APIKey asks the signer which API key its private key belongs to
+func (s *External) APIKey(ctx context.Context) (string, error) {
+	resp, err := s.call(ctx, request{Method: "api_key"})
+	if err != nil {
+		return "", err
+	}
+	if resp.APIKey == "" {
+		return "", fmt.Errorf("signer returned no API key")
+	}
+	return resp.APIKey, nil
+}
+
+// Close disconnects from the signer, waiting for a started process to exit
+func (s *External) Close() error {
+	return s.close()
+}
@@ -0,0 +1,120 @@
+// Package signer provides api.Signer implementations that keep the Ed25519
+// private key out of plain sight: an encrypted key file unlocked with a
+// passphrase once per session, and an external signer process that holds
+// the key itself and is reached over a line-based JSON protocol.
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"dazedtrader/auth"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// keyFileVersion is the format written by WriteKeyFile
+const keyFileVersion = 1
+
+// KeyFile is an API key stored with its private key sealed by a passphrase.
+// The API key and public key are kept in the clear so the file can be
+// identified without unlocking it.
+type KeyFile struct {
+	Version    int          `json:"version"`
+	APIKey     string       `json:"api_key"`
+	PublicKey  []byte       `json:"public_key"`
+	PrivateKey *auth.Sealed `json:"private_key"`
+}
+
+// WriteKeyFile seals privateKey with passphrase and writes it, with apiKey,
+// to path. The file is only readable by the current user.
+func WriteKeyFile(path, apiKey string, privateKey ed25519.PrivateKey, passphrase []byte) error {
+	sealed, err := auth.Seal(privateKey, passphrase)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(KeyFile{
+		Version:    keyFileVersion,
+		APIKey:     apiKey,
+		PublicKey:  privateKey.Public().(ed25519.PublicKey),
+		PrivateKey: sealed,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal key file: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	return nil
+}
+
+// ReadKeyFile reads a key file written by WriteKeyFile without unlocking it
+func ReadKeyFile(path string) (*KeyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	var file KeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %w", err)
+	}
+	if file.Version != keyFileVersion {
+		return nil, fmt.Errorf("unsupported key file version %d", file.Version)
+	}
+	if file.APIKey == "" || len(file.PublicKey) != ed25519.PublicKeySize || file.PrivateKey == nil {
+		return nil, fmt.Errorf("key file is incomplete")
+	}
+
+	return &file, nil
+}
+
+// Unlock checks passphrase against the key file and returns a signer for
+// its key. Only the key derived from the passphrase is kept; the private
+// key is decrypted for each signature and wiped straight after.
+func (f *KeyFile) Unlock(passphrase []byte) (*FileSigner, error) {
+	key, err := f.PrivateKey.DeriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := f.PrivateKey.OpenWithKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(privateKey)
+
+	if len(privateKey) != ed25519.PrivateKeySize ||
+		!bytes.Equal(ed25519.PrivateKey(privateKey).Public().(ed25519.PublicKey), f.PublicKey) {
+		return nil, fmt.Errorf("key file's private key doesn't match its public key")
+	}
+
+	return &FileSigner{sealed: f.PrivateKey, key: key}, nil
+}
+
+// FileSigner signs with the private key of an unlocked key file
+type FileSigner struct {
+	sealed *auth.Sealed
+	key    []byte
+}
+
+// Sign decrypts the private key, signs message and wipes the key again
+func (s *FileSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	privateKey, err := s.sealed.OpenWithKey(s.key)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(privateKey)
+
+	return ed25519.Sign(ed25519.PrivateKey(privateKey), message), nil
+}
+
+// wipe overwrites secret key material that is no longer needed
+func wipe(secret []byte) {
+	for i := range secret {
+		secret[i] = 0
+	}
+}
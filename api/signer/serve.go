@@ -0,0 +1,47 @@
+package signer
+
+import (
+	"bufio"
+	"context"
+	"dazedtrader/api"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Serve answers protocol requests read from r on w, signing with signer on
+// behalf of apiKey, until r is exhausted. It is the other end of External,
+// for building a signer process around any api.Signer.
+func Serve(ctx context.Context, r io.Reader, w io.Writer, apiKey string, signer api.Signer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxLine)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return fmt.Errorf("invalid signer request: %w", err)
+		}
+
+		resp := response{ID: req.ID}
+		switch req.Method {
+		case "sign":
+			signature, err := signer.Sign(ctx, req.Message)
+			if err != nil {
+				resp.Error = err.Error()
+			} else {
+				resp.Signature = signature
+			}
+		case "api_key":
+			resp.APIKey = apiKey
+		default:
+			resp.Error = fmt.Sprintf("unknown method %q", req.Method)
+		}
+
+		if err := encoder.Encode(resp); err != nil {
+			return fmt.Errorf("failed to answer signer request: %w", err)
+		}
+	}
+
+	return scanner.Err()
+}
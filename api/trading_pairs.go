@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"dazedtrader/decimal"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// TradingPair describes the order rules Robinhood enforces for a symbol.
+// Quantities must be a multiple of AssetIncrement and prices a multiple of
+// QuoteIncrement.
+type TradingPair struct {
+	Symbol         string
+	AssetCode      string
+	QuoteCode      string
+	AssetIncrement decimal.Decimal
+	QuoteIncrement decimal.Decimal
+	MinOrderSize   decimal.Decimal
+	MaxOrderSize   decimal.Decimal
+	Status         string
+
+	Extra map[string]json.RawMessage // Fields the client doesn't know, as raw JSON
+}
+
+// IsTradable reports whether new orders are accepted for the pair
+func (p TradingPair) IsTradable() bool {
+	return p.Status == "tradable"
+}
+
+// RoundQuantity rounds quantity down to the asset increment
+func (p TradingPair) RoundQuantity(quantity decimal.Decimal) decimal.Decimal {
+	return quantity.RoundDownTo(p.AssetIncrement)
+}
+
+// RoundPrice rounds price to the nearest quote increment
+func (p TradingPair) RoundPrice(price decimal.Decimal) decimal.Decimal {
+	return price.RoundTo(p.QuoteIncrement)
+}
+
+// RoundQuoteAmount rounds a USD order amount down to the quote increment
+func (p TradingPair) RoundQuoteAmount(amount decimal.Decimal) decimal.Decimal {
+	return amount.RoundDownTo(p.QuoteIncrement)
+}
+
+// ValidateQuantity checks quantity against the pair's status, size limits and increment
+func (p TradingPair) ValidateQuantity(quantity decimal.Decimal) error {
+	if !p.IsTradable() {
+		return fmt.Errorf("%s is not currently tradable", p.Symbol)
+	}
+	if p.MinOrderSize.IsPositive() && quantity.LessThan(p.MinOrderSize) {
+		return fmt.Errorf("minimum order size for %s is %s %s", p.Symbol, p.MinOrderSize, p.AssetCode)
+	}
+	if p.MaxOrderSize.IsPositive() && quantity.GreaterThan(p.MaxOrderSize) {
+		return fmt.Errorf("maximum order size for %s is %s %s", p.Symbol, p.MaxOrderSize, p.AssetCode)
+	}
+	if !quantity.IsMultipleOf(p.AssetIncrement) {
+		return fmt.Errorf("quantity must be a multiple of %s %s", p.AssetIncrement, p.AssetCode)
+	}
+	return nil
+}
+
+// ValidatePrice checks that price is positive and a multiple of the quote increment
+func (p TradingPair) ValidatePrice(price decimal.Decimal) error {
+	if !price.IsPositive() {
+		return fmt.Errorf("price must be a positive number")
+	}
+	if !price.IsMultipleOf(p.QuoteIncrement) {
+		return fmt.Errorf("price must be a multiple of %s", p.QuoteIncrement)
+	}
+	return nil
+}
+
+// GetTradingPairs retrieves the rules for the given symbols, or for every
+// supported pair when symbols is empty, following every page
+func (c *CryptoClient) GetTradingPairs(symbols []string) ([]TradingPair, error) {
+	return c.GetTradingPairsContext(context.Background(), symbols)
+}
+
+// GetTradingPairsContext is like GetTradingPairs but aborts when ctx is done
+func (c *CryptoClient) GetTradingPairsContext(ctx context.Context, symbols []string) ([]TradingPair, error) {
+	endpoint := c.TradingURL + "/trading_pairs/"
+	if len(symbols) > 0 {
+		endpoint += "?" + url.Values{"symbol": symbols}.Encode()
+	}
+
+	return newPaginator(c, endpoint, pageDecoder(c, "trading pair", decodeTradingPair)).AllContext(ctx, 0)
+}
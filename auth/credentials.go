@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrReadOnlyStore is returned when saving to or clearing a credential store
+// that is managed outside the app
+var ErrReadOnlyStore = errors.New("credential store is read-only")
+
+// CredentialStore supplies the API key of a profile
+type CredentialStore interface {
+	// Load returns the stored key, still locked if it is encrypted, or nil
+	// when there is none
+	Load() (*APIKeyFile, error)
+
+	// Save replaces the stored key with credentials sealed with passphrase
+	Save(apiKey, username string, expiresAt int64, passphrase []byte) error
+
+	// Clear removes the stored key
+	Clear() error
+
+	// ReadOnly reports whether the key is managed outside the app, in which
+	// case Save and Clear fail with ErrReadOnlyStore
+	ReadOnly() bool
+}
+
+// FileStore keeps a profile's key in an encrypted file in the config directory
+type FileStore struct {
+	Profile string
+}
+
+func (s FileStore) Load() (*APIKeyFile, error) {
+	return LoadAPIKey(s.Profile)
+}
+
+func (s FileStore) Save(apiKey, username string, expiresAt int64, passphrase []byte) error {
+	return SaveAPIKey(s.Profile, apiKey, username, expiresAt, passphrase)
+}
+
+func (s FileStore) Clear() error {
+	return ClearAPIKey(s.Profile)
+}
+
+func (s FileStore) ReadOnly() bool {
+	return false
+}
+
+// Environment variables read by EnvStore when no variable is configured
+const (
+	CredentialsEnv = "DAZEDTRADER_CREDENTIALS" // apikey:privatekey
+	APIKeyEnv      = "DAZEDTRADER_API_KEY"
+	PrivateKeyEnv  = "DAZEDTRADER_PRIVATE_KEY" // base64
+)
+
+// EnvStore reads the key from environment variables, for headless runs.
+// Variable names one holding "apikey:privatekey"; when empty the key is
+// read from CredentialsEnv, or from APIKeyEnv and PrivateKeyEnv together.
+type EnvStore struct {
+	Variable string
+}
+
+func (s EnvStore) Load() (*APIKeyFile, error) {
+	var credentials string
+	switch {
+	case s.Variable != "":
+		credentials = os.Getenv(s.Variable)
+	case os.Getenv(CredentialsEnv) != "":
+		credentials = os.Getenv(CredentialsEnv)
+	case os.Getenv(APIKeyEnv) != "" && os.Getenv(PrivateKeyEnv) != "":
+		credentials = os.Getenv(APIKeyEnv) + ":" + os.Getenv(PrivateKeyEnv)
+	}
+	if credentials == "" {
+		return nil, nil
+	}
+
+	return &APIKeyFile{APIKey: strings.TrimSpace(credentials), Username: "Crypto Trader"}, nil
+}
+
+func (s EnvStore) Save(apiKey, username string, expiresAt int64, passphrase []byte) error {
+	return ErrReadOnlyStore
+}
+
+func (s EnvStore) Clear() error {
+	return ErrReadOnlyStore
+}
+
+func (s EnvStore) ReadOnly() bool {
+	return true
+}
+
+// commandTimeout bounds how long a credential command may take, leaving
+// time to answer a GPG pinentry prompt
+const commandTimeout = 2 * time.Minute
+
+// CommandStore runs an external command, e.g. `pass show robinhood`, and
+// takes the key from the first line of its output
+type CommandStore struct {
+	Command []string // program and arguments
+}
+
+func (s CommandStore) Load() (*APIKeyFile, error) {
+	run, err := s.NewRun(nil)
+	if err != nil {
+		return nil, err
+	}
+	return run.Result(run.Run())
+}
+
+// CommandRun is one run of a credential command. Its output is captured
+// for Result; Stdin is left unset so a caller that hands the command the
+// terminal, e.g. with tea.ExecProcess, lets it prompt for a passphrase.
+type CommandRun struct {
+	*exec.Cmd
+
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+	cancel context.CancelFunc
+}
+
+// NewRun prepares a run of the command, bounded by commandTimeout. Stderr
+// is kept for the error message and also copied to stderr when it is set.
+func (s CommandStore) NewRun(stderr io.Writer) (*CommandRun, error) {
+	if len(s.Command) == 0 {
+		return nil, fmt.Errorf("no credential command configured")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	run := &CommandRun{cancel: cancel}
+	run.Cmd = exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
+	run.Cmd.Stdout = &run.stdout
+	run.Cmd.Stderr = &run.stderr
+	if stderr != nil {
+		run.Cmd.Stderr = io.MultiWriter(stderr, &run.stderr)
+	}
+	return run, nil
+}
+
+// Result reads the key the command printed, given the error it exited with
+func (r *CommandRun) Result(err error) (*APIKeyFile, error) {
+	r.cancel()
+	if err != nil {
+		if detail := strings.TrimSpace(r.stderr.String()); detail != "" {
+			return nil, fmt.Errorf("credential command failed: %w: %s", err, detail)
+		}
+		return nil, fmt.Errorf("credential command failed: %w", err)
+	}
+
+	credentials, _, _ := strings.Cut(r.stdout.String(), "\n")
+	credentials = strings.TrimSpace(credentials)
+	if credentials == "" {
+		return nil, fmt.Errorf("credential command printed no key")
+	}
+
+	return &APIKeyFile{APIKey: credentials, Username: "Crypto Trader"}, nil
+}
+
+func (s CommandStore) Save(apiKey, username string, expiresAt int64, passphrase []byte) error {
+	return ErrReadOnlyStore
+}
+
+func (s CommandStore) Clear() error {
+	return ErrReadOnlyStore
+}
+
+func (s CommandStore) ReadOnly() bool {
+	return true
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// OrderIntent is an order about to be sent to Robinhood. It is saved before
+// the request goes out, so its client_order_id survives a timeout or a crash
+// and the order can be looked up instead of being placed a second time.
+type OrderIntent struct {
+	ClientOrderID string    `json:"client_order_id"`
+	KeyID         string    `json:"key_id"` // Fingerprint of the API key that sent it
+	AccountNumber string    `json:"account_number,omitempty"`
+	Side          string    `json:"side"`
+	Type          string    `json:"type"`
+	Symbol        string    `json:"symbol"`
+	Quantity      string    `json:"quantity,omitempty"`
+	QuoteAmount   string    `json:"quote_amount,omitempty"`
+	LimitPrice    string    `json:"limit_price,omitempty"`
+	StopPrice     string    `json:"stop_price,omitempty"`
+	TimeInForce   string    `json:"time_in_force,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SameOrder reports whether other describes the same order, ignoring the
+// client order ID and when it was recorded
+func (i OrderIntent) SameOrder(other OrderIntent) bool {
+	i.ClientOrderID, other.ClientOrderID = "", ""
+	i.CreatedAt, other.CreatedAt = time.Time{}, time.Time{}
+	return i == other
+}
+
+// IntentStore keeps the order intents whose outcome hasn't been confirmed
+// yet. A store without a path lives in memory only.
+type IntentStore struct {
+	path    string
+	mu      sync.Mutex
+	intents []OrderIntent
+}
+
+// OpenIntentStore loads the pending order intents saved in the config
+// directory
+func OpenIntentStore() (*IntentStore, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	store := &IntentStore{path: filepath.Join(configDir, "pending_orders.json")}
+	data, err := os.ReadFile(store.path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pending orders file: %w", err)
+	}
+	if err := json.Unmarshal(data, &store.intents); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pending orders: %w", err)
+	}
+
+	return store, nil
+}
+
+// NewMemoryIntentStore returns a store that is never written to disk
+func NewMemoryIntentStore() *IntentStore {
+	return &IntentStore{}
+}
+
+// List returns the pending intents, oldest first
+func (s *IntentStore) List() []OrderIntent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]OrderIntent(nil), s.intents...)
+}
+
+// Put records intent, replacing any intent with the same client order ID.
+// The intent is on disk once Put returns without an error.
+func (s *IntentStore) Put(intent OrderIntent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intents := make([]OrderIntent, 0, len(s.intents)+1)
+	for _, existing := range s.intents {
+		if existing.ClientOrderID != intent.ClientOrderID {
+			intents = append(intents, existing)
+		}
+	}
+	intents = append(intents, intent)
+
+	if err := s.write(intents); err != nil {
+		return err
+	}
+	s.intents = intents
+	return nil
+}
+
+// Remove drops the intent with the given client order ID once its outcome is
+// known
+func (s *IntentStore) Remove(clientOrderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intents := make([]OrderIntent, 0, len(s.intents))
+	for _, existing := range s.intents {
+		if existing.ClientOrderID != clientOrderID {
+			intents = append(intents, existing)
+		}
+	}
+	if len(intents) == len(s.intents) {
+		return nil
+	}
+
+	if err := s.write(intents); err != nil {
+		return err
+	}
+	s.intents = intents
+	return nil
+}
+
+// write replaces the pending orders file. The new contents are synced to a
+// temporary file first and renamed over the old one, so a crash leaves
+// either the old or the new list, never a partial one. Callers hold mu.
+func (s *IntentStore) write(intents []OrderIntent) error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(intents)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending orders: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write pending orders file: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write pending orders file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write pending orders file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write pending orders file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace pending orders file: %w", err)
+	}
+
+	return nil
+}
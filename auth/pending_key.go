@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PendingKey is a generated private key waiting for the API key Robinhood
+// issues for its public half. The private key is sealed with the passphrase
+// the credentials are saved with once the API key is entered.
+type PendingKey struct {
+	PublicKey  string  `json:"public_key"` // base64, as registered with Robinhood
+	PrivateKey *Sealed `json:"sealed_private_key"`
+	CreatedAt  int64   `json:"created_at"`
+}
+
+// getPendingKeyFile returns the file holding the profile's pending key
+func getPendingKeyFile(profile string) (string, error) {
+	if err := ValidateProfileName(profile); err != nil {
+		return "", err
+	}
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	pendingDir := filepath.Join(configDir, "pending_keys")
+	if err := os.MkdirAll(pendingDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create pending key directory: %w", err)
+	}
+
+	return filepath.Join(pendingDir, profile+".json"), nil
+}
+
+// SavePendingKey stores privateKey sealed with passphrase until its API key
+// is entered, replacing any key generated for the profile earlier
+func SavePendingKey(profile string, privateKey ed25519.PrivateKey, passphrase []byte) error {
+	pendingKeyFile, err := getPendingKeyFile(profile)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := Seal(privateKey, passphrase)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(PendingKey{
+		PublicKey:  base64.StdEncoding.EncodeToString(privateKey.Public().(ed25519.PublicKey)),
+		PrivateKey: sealed,
+		CreatedAt:  time.Now().Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending key: %w", err)
+	}
+
+	if err := os.WriteFile(pendingKeyFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write pending key file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadPendingKey reads the profile's pending key without unlocking it,
+// returning nil when there is none
+func LoadPendingKey(profile string) (*PendingKey, error) {
+	pendingKeyFile, err := getPendingKeyFile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(pendingKeyFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pending key file: %w", err)
+	}
+
+	var pending PendingKey
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pending key: %w", err)
+	}
+	if pending.PrivateKey == nil {
+		return nil, fmt.Errorf("pending key file holds no private key")
+	}
+
+	return &pending, nil
+}
+
+// ClearPendingKey removes the profile's pending key
+func ClearPendingKey(profile string) error {
+	pendingKeyFile, err := getPendingKeyFile(profile)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(pendingKeyFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove pending key file: %w", err)
+	}
+
+	return nil
+}
+
+// Unlock decrypts the private key with passphrase, checking that it still
+// belongs to the registered public key
+func (p *PendingKey) Unlock(passphrase []byte) (ed25519.PrivateKey, error) {
+	secret, err := p.PrivateKey.Open(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secret) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("pending private key must be %d bytes, got %d", ed25519.PrivateKeySize, len(secret))
+	}
+	privateKey := ed25519.PrivateKey(secret)
+	publicKey := base64.StdEncoding.EncodeToString(privateKey.Public().(ed25519.PublicKey))
+	if publicKey != p.PublicKey {
+		return nil, fmt.Errorf("pending private key doesn't match its public key")
+	}
+
+	return privateKey, nil
+}
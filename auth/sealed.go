@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2id parameters for new seals: 64 MiB and three passes take a
+// fraction of a second to unlock but make guessing passphrases expensive
+const (
+	sealTime    = 3
+	sealMemory  = 64 * 1024 // KiB
+	sealThreads = 4
+	sealKeySize = 32
+)
+
+// Upper bounds on the Argon2id parameters read from a sealed file, so a
+// tampered file can't make unlocking run for hours or exhaust memory
+const (
+	maxSealTime    = 10
+	maxSealMemory  = 1024 * 1024 // KiB, 1 GiB
+	maxSealThreads = 64
+)
+
+// sealedData is bound to every ciphertext, so a sealed value can't be
+// passed off as anything other than a DazedTrader secret
+var sealedData = []byte("dazedtrader sealed v1")
+
+// ErrWrongPassphrase is returned when a sealed secret can't be opened,
+// either because the passphrase is wrong or the data was altered
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+
+// Sealed is a secret encrypted with a key derived from a passphrase: the key
+// comes from Argon2id and the secret is sealed with AES-256-GCM. The KDF
+// parameters are stored alongside so they can be raised later.
+type Sealed struct {
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt"`
+	Time       uint32 `json:"time"`
+	Memory     uint32 `json:"memory"` // KiB
+	Threads    uint8  `json:"threads"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Seal encrypts secret with a key derived from passphrase
+func Seal(secret, passphrase []byte) (*Sealed, error) {
+	sealed := &Sealed{
+		KDF:     "argon2id",
+		Salt:    make([]byte, 16),
+		Time:    sealTime,
+		Memory:  sealMemory,
+		Threads: sealThreads,
+	}
+	if _, err := rand.Read(sealed.Salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key, err := sealed.DeriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := sealed.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(sealed.Nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed.Ciphertext = aead.Seal(nil, sealed.Nonce, secret, sealedData)
+
+	return sealed, nil
+}
+
+// DeriveKey derives the encryption key from passphrase. This is the slow
+// step; keep the key to open the secret again without repeating it.
+func (s *Sealed) DeriveKey(passphrase []byte) ([]byte, error) {
+	if s.Time == 0 || s.Memory == 0 || s.Threads == 0 {
+		return nil, fmt.Errorf("invalid key derivation parameters: time, memory and threads must be set")
+	}
+	if s.Time > maxSealTime || s.Memory > maxSealMemory || s.Threads > maxSealThreads {
+		return nil, fmt.Errorf("key derivation parameters exceed the limits of %d passes, %d KiB and %d threads", maxSealTime, maxSealMemory, maxSealThreads)
+	}
+	return argon2.IDKey(passphrase, s.Salt, s.Time, s.Memory, s.Threads, sealKeySize), nil
+}
+
+// Open decrypts the secret with a key derived from passphrase
+func (s *Sealed) Open(passphrase []byte) ([]byte, error) {
+	key, err := s.DeriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return s.OpenWithKey(key)
+}
+
+// OpenWithKey decrypts the secret with a key returned by DeriveKey
+func (s *Sealed) OpenWithKey(key []byte) ([]byte, error) {
+	aead, err := s.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != aead.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+
+	secret, err := aead.Open(nil, s.Nonce, s.Ciphertext, sealedData)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return secret, nil
+}
+
+// aead returns the cipher for key, rejecting unknown KDFs
+func (s *Sealed) aead(key []byte) (cipher.AEAD, error) {
+	if s.KDF != "argon2id" {
+		return nil, fmt.Errorf("unsupported key derivation %q", s.KDF)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
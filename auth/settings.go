@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Settings is the optional ~/.config/dazedtrader/config.json, e.g.
+//
+//	{
+//	  "credentials": {
+//	    "default": {"backend": "command", "command": ["pass", "show", "robinhood"]},
+//	    "ci":      {"backend": "env"}
+//	  }
+//	}
+type Settings struct {
+	// Credentials selects where each profile's key comes from, by profile
+	// name. Profiles not listed keep their key in an encrypted file.
+	Credentials map[string]CredentialSettings `json:"credentials"`
+}
+
+// CredentialSettings selects and configures a credential store
+type CredentialSettings struct {
+	Backend  string   `json:"backend"`            // "file", "env" or "command"
+	Variable string   `json:"variable,omitempty"` // env: variable holding apikey:privatekey
+	Command  []string `json:"command,omitempty"`  // command: program and arguments
+}
+
+func getSettingsFile() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "config.json"), nil
+}
+
+// LoadSettings reads config.json, returning empty settings when there is none
+func LoadSettings() (*Settings, error) {
+	settingsFile, err := getSettingsFile()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(settingsFile)
+	if os.IsNotExist(err) {
+		return &Settings{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var settings Settings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	for profile := range settings.Credentials {
+		if _, err := settings.CredentialStore(profile); err != nil {
+			return nil, fmt.Errorf("config file: %w", err)
+		}
+	}
+
+	return &settings, nil
+}
+
+// CredentialStore returns the store configured for profile
+func (s *Settings) CredentialStore(profile string) (CredentialStore, error) {
+	if err := ValidateProfileName(profile); err != nil {
+		return nil, err
+	}
+
+	var config CredentialSettings
+	if s != nil {
+		config = s.Credentials[profile]
+	}
+
+	switch config.Backend {
+	case "", "file":
+		return FileStore{Profile: profile}, nil
+	case "env":
+		return EnvStore{Variable: config.Variable}, nil
+	case "command":
+		if len(config.Command) == 0 {
+			return nil, fmt.Errorf("profile %q: the command backend needs a command", profile)
+		}
+		return CommandStore{Command: config.Command}, nil
+	}
+	return nil, fmt.Errorf("profile %q: unknown credential backend %q", profile, config.Backend)
+}
+
+// Profiles returns the profiles with a stored key or a configured credential
+// store, sorted by name with the default profile first
+func (s *Settings) Profiles() ([]string, error) {
+	profiles, err := ListProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool)
+	for _, profile := range profiles {
+		known[profile] = true
+	}
+	if s != nil {
+		for profile := range s.Credentials {
+			if !known[profile] {
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		if profiles[i] == DefaultProfile || profiles[j] == DefaultProfile {
+			return profiles[i] == DefaultProfile
+		}
+		return profiles[i] < profiles[j]
+	})
+	return profiles, nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 type APIKeyData struct {
@@ -13,7 +15,8 @@ type APIKeyData struct {
 	ExpiresAt int64  `json:"expires_at"`
 }
 
-func getAPIKeyFile() (string, error) {
+// getConfigDir returns ~/.config/dazedtrader, creating it if needed
+func getConfigDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home directory: %w", err)
@@ -24,35 +27,171 @@ func getAPIKeyFile() (string, error) {
 		return "", fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	return filepath.Join(configDir, "api_key.json"), nil
+	return configDir, nil
 }
 
-func SaveAPIKey(apiKey, username string, expiresAt int64) error {
-	apiKeyFile, err := getAPIKeyFile()
+// DefaultProfile is used when no profile is chosen. Its key is kept in
+// api_key.json, where it was stored before profiles existed.
+const DefaultProfile = "default"
+
+// maxProfileName bounds the length of profile names
+const maxProfileName = 32
+
+// ValidateProfileName checks that name can be used as a profile: letters,
+// digits, '-' and '_' only, so it is always a safe file name
+func ValidateProfileName(name string) error {
+	if name == "" || len(name) > maxProfileName {
+		return fmt.Errorf("profile name must be 1 to %d characters", maxProfileName)
+	}
+	for _, char := range name {
+		if !(char >= 'a' && char <= 'z') && !(char >= 'A' && char <= 'Z') &&
+			!(char >= '0' && char <= '9') && char != '-' && char != '_' {
+			return fmt.Errorf("profile name %q may only contain letters, digits, '-' and '_'", name)
+		}
+	}
+	return nil
+}
+
+// getProfilesDir returns the directory holding the keys of named profiles
+func getProfilesDir() (string, error) {
+	configDir, err := getConfigDir()
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	profilesDir := filepath.Join(configDir, "profiles")
+	if err := os.MkdirAll(profilesDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create profiles directory: %w", err)
+	}
+
+	return profilesDir, nil
+}
+
+func getAPIKeyFile(profile string) (string, error) {
+	if err := ValidateProfileName(profile); err != nil {
+		return "", err
 	}
 
-	apiKeyData := APIKeyData{
+	if profile == DefaultProfile {
+		configDir, err := getConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(configDir, "api_key.json"), nil
+	}
+
+	profilesDir, err := getProfilesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(profilesDir, profile+".json"), nil
+}
+
+// ListProfiles returns the profiles that have a stored API key, sorted by
+// name with the default profile first
+func ListProfiles() ([]string, error) {
+	var profiles []string
+	if file, err := getAPIKeyFile(DefaultProfile); err != nil {
+		return nil, err
+	} else if _, err := os.Stat(file); err == nil {
+		profiles = append(profiles, DefaultProfile)
+	}
+
+	profilesDir, err := getProfilesDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(profilesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var named []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if ok && !entry.IsDir() && name != DefaultProfile && ValidateProfileName(name) == nil {
+			named = append(named, name)
+		}
+	}
+	sort.Strings(named)
+
+	return append(profiles, named...), nil
+}
+
+// APIKeyFile is api_key.json as read from disk, before it is unlocked. The
+// credentials are sealed with the user's passphrase; files written before
+// encryption was added hold them in APIKey instead.
+type APIKeyFile struct {
+	APIKey    string  `json:"api_key,omitempty"`
+	Sealed    *Sealed `json:"sealed_api_key,omitempty"`
+	Username  string  `json:"username"`
+	ExpiresAt int64   `json:"expires_at"`
+}
+
+// Encrypted reports whether the credentials need a passphrase to unlock
+func (f *APIKeyFile) Encrypted() bool {
+	return f.Sealed != nil
+}
+
+// Unlock decrypts the credentials with passphrase. Plaintext files unlock
+// with any passphrase, so they can be migrated with SaveAPIKey.
+func (f *APIKeyFile) Unlock(passphrase []byte) (*APIKeyData, error) {
+	apiKey := f.APIKey
+	if f.Encrypted() {
+		secret, err := f.Sealed.Open(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		apiKey = string(secret)
+	}
+
+	return &APIKeyData{
 		APIKey:    apiKey,
-		Username:  username,
-		ExpiresAt: expiresAt,
+		Username:  f.Username,
+		ExpiresAt: f.ExpiresAt,
+	}, nil
+}
+
+// SaveAPIKey stores the profile's credentials sealed with passphrase,
+// replacing any earlier file, including one written in plaintext
+func SaveAPIKey(profile, apiKey, username string, expiresAt int64, passphrase []byte) error {
+	apiKeyFile, err := getAPIKeyFile(profile)
+	if err != nil {
+		return err
 	}
 
-	data, err := json.Marshal(apiKeyData)
+	sealed, err := Seal([]byte(apiKey), passphrase)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(APIKeyFile{
+		Sealed:    sealed,
+		Username:  username,
+		ExpiresAt: expiresAt,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to marshal API key data: %w", err)
 	}
 
-	if err := os.WriteFile(apiKeyFile, data, 0600); err != nil {
+	// Write a new file and rename it over the old one, so a plaintext file
+	// is never left half overwritten
+	tmp := apiKeyFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write API key file: %w", err)
+	}
+	if err := os.Rename(tmp, apiKeyFile); err != nil {
+		os.Remove(tmp)
 		return fmt.Errorf("failed to write API key file: %w", err)
 	}
 
 	return nil
 }
 
-func LoadAPIKey() (*APIKeyData, error) {
-	apiKeyFile, err := getAPIKeyFile()
+// LoadAPIKey reads the profile's API key file without unlocking it,
+// returning nil when there is none
+func LoadAPIKey(profile string) (*APIKeyFile, error) {
+	apiKeyFile, err := getAPIKeyFile(profile)
 	if err != nil {
 		return nil, err
 	}
@@ -66,16 +205,17 @@ func LoadAPIKey() (*APIKeyData, error) {
 		return nil, fmt.Errorf("failed to read API key file: %w", err)
 	}
 
-	var apiKeyData APIKeyData
-	if err := json.Unmarshal(data, &apiKeyData); err != nil {
+	var file APIKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal API key data: %w", err)
 	}
 
-	return &apiKeyData, nil
+	return &file, nil
 }
 
-func ClearAPIKey() error {
-	apiKeyFile, err := getAPIKeyFile()
+// ClearAPIKey removes the profile's stored API key
+func ClearAPIKey(profile string) error {
+	apiKeyFile, err := getAPIKeyFile(profile)
 	if err != nil {
 		return err
 	}
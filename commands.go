@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"dazedtrader/api"
+	"dazedtrader/api/signer"
+	"dazedtrader/auth"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// subcommands run instead of the TUI when named as the first argument
+var subcommands = map[string]func(args []string) error{
+	"signer":   runSigner,
+	"seal-key": runSealKey,
+	"keygen":   runKeygen,
+}
+
+// runSigner serves the external signer protocol, on stdin and stdout or on
+// a Unix socket, so the TUI can sign without ever holding the private key
+func runSigner(args []string) error {
+	flags := flag.NewFlagSet("signer", flag.ExitOnError)
+	socket := flags.String("socket", "", "listen on this Unix socket instead of stdin/stdout")
+	keyFile := flags.String("key-file", "", "sign with this encrypted key file (default: credentials from the environment, as for the env credential backend)")
+	flags.Parse(args)
+
+	apiKey, s, err := signerCredentials(*keyFile)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *socket == "" {
+		return signer.Serve(ctx, os.Stdin, os.Stdout, apiKey, s)
+	}
+
+	// Replace a socket left behind by an earlier run, but nothing else
+	if info, err := os.Lstat(*socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(*socket)
+	}
+	listener, err := net.Listen("unix", *socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	if err := os.Chmod(*socket, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to restrict socket: %w", err)
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		go func() {
+			defer conn.Close()
+			signer.Serve(ctx, conn, conn, apiKey, s)
+		}()
+	}
+}
+
+// signerCredentials returns the key the signer subcommand signs with: an
+// unlocked key file, or plain credentials from the environment
+func signerCredentials(keyFile string) (string, api.Signer, error) {
+	if keyFile != "" {
+		return unlockKeyFile(keyFile)
+	}
+
+	stored, err := auth.EnvStore{}.Load()
+	if err != nil {
+		return "", nil, err
+	}
+	if stored == nil {
+		return "", nil, fmt.Errorf("no key: pass -key-file or set %s", auth.CredentialsEnv)
+	}
+	apiKey, privateKey, err := api.ParseCredentials(stored.APIKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return apiKey, api.NewKeySigner(privateKey), nil
+}
+
+// runSealKey encrypts "apikey:privatekey" credentials into a key file for
+// -key-file, so the private key is never stored in the clear
+func runSealKey(args []string) error {
+	flags := flag.NewFlagSet("seal-key", flag.ExitOnError)
+	output := flags.String("o", "", "write the key file to this path")
+	flags.Parse(args)
+
+	if *output == "" {
+		return errors.New("-o is required")
+	}
+	if _, err := os.Stat(*output); err == nil {
+		return fmt.Errorf("%s already exists", *output)
+	}
+
+	credentials, err := readSecret("API credentials (apikey:privatekey): ")
+	if err != nil {
+		return err
+	}
+	apiKey, privateKey, err := api.ParseCredentials(strings.TrimSpace(string(credentials)))
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := readNewPassphrase()
+	if err != nil {
+		return err
+	}
+
+	if err := signer.WriteKeyFile(*output, apiKey, privateKey, passphrase); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Key for %s sealed to %s\n", apiKey, *output)
+	return nil
+}
+
+// runKeygen generates the key pair a new API key is created with. The
+// private key is saved encrypted as the profile's pending key and the public
+// key printed for Robinhood; the app finishes setup once the API key is
+// entered.
+func runKeygen(args []string) error {
+	flags := flag.NewFlagSet("keygen", flag.ExitOnError)
+	profile := flags.String("profile", auth.DefaultProfile, "generate the key pair for this named profile")
+	force := flags.Bool("force", false, "replace a key pair that is still waiting for its API key")
+	flags.Parse(args)
+
+	settings, err := auth.LoadSettings()
+	if err != nil {
+		return err
+	}
+	store, err := settings.CredentialStore(*profile)
+	if err != nil {
+		return err
+	}
+	if store.ReadOnly() {
+		return fmt.Errorf("profile %s takes its key from the credential backend set in config.json", *profile)
+	}
+
+	pending, err := auth.LoadPendingKey(*profile)
+	if err != nil {
+		return err
+	}
+	if pending != nil && !*force {
+		return fmt.Errorf("the key pair generated earlier is still waiting for its API key (public key %s); enter it in the app or pass -force to replace it", pending.PublicKey)
+	}
+
+	passphrase, err := readNewPassphrase()
+	if err != nil {
+		return err
+	}
+
+	publicKey, privateKey, err := api.GenerateKey()
+	if err != nil {
+		return err
+	}
+	if err := auth.SavePendingKey(*profile, privateKey, passphrase); err != nil {
+		return err
+	}
+
+	// Only the public key goes to stdout, so it can be piped to the clipboard
+	fmt.Fprintln(os.Stderr, "Create an API key in your Robinhood account with this public key:")
+	fmt.Println(publicKey)
+
+	command := "dazedtrader"
+	if *profile != auth.DefaultProfile {
+		command += " -profile " + *profile
+	}
+	fmt.Fprintf(os.Stderr, "Then run %s, choose Setup API Key and press Ctrl+G to enter the API key.\n", command)
+	return nil
+}
+
+// readNewPassphrase asks for a new passphrase twice on the terminal
+func readNewPassphrase() ([]byte, error) {
+	passphrase, err := readSecret("New passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase can't be empty")
+	}
+	confirm, err := readSecret("Repeat passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(passphrase, confirm) {
+		return nil, errors.New("passphrases don't match")
+	}
+	return passphrase, nil
+}
+
+// startSigner sets up the signer chosen on the command line, returning its
+// API key, the signer and a function to release it
+func startSigner(keyFile, command, socket string) (string, api.Signer, func(), error) {
+	if keyFile != "" {
+		apiKey, s, err := unlockKeyFile(keyFile)
+		return apiKey, s, func() {}, err
+	}
+
+	var external *signer.External
+	var err error
+	if command != "" {
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			return "", nil, nil, errors.New("signer command is empty")
+		}
+		external, err = signer.StartProcess(fields[0], fields[1:]...)
+	} else {
+		external, err = signer.Dial(socket)
+	}
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	apiKey, err := external.APIKey(ctx)
+	if err != nil {
+		external.Close()
+		return "", nil, nil, err
+	}
+	return apiKey, external, func() { external.Close() }, nil
+}
+
+// unlockKeyFile reads a key file and unlocks it with a passphrase typed on
+// the terminal
+func unlockKeyFile(path string) (string, api.Signer, error) {
+	file, err := signer.ReadKeyFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	passphrase, err := readSecret(fmt.Sprintf("Passphrase for %s: ", path))
+	if err != nil {
+		return "", nil, err
+	}
+	s, err := file.Unlock(passphrase)
+	if err != nil {
+		return "", nil, err
+	}
+	return file.APIKey, s, nil
+}
+
+// readSecret prompts on the controlling terminal and reads a line without
+// echoing it. The terminal is used even when stdin and stdout are taken, as
+// they are for a signer serving on stdio.
+func readSecret(prompt string) ([]byte, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("a terminal is needed to enter secrets: %w", err)
+	}
+	defer tty.Close()
+
+	fmt.Fprint(tty, prompt)
+	secret, err := term.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(tty)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from terminal: %w", err)
+	}
+	return secret, nil
+}
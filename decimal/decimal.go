@@ -0,0 +1,331 @@
+// Package decimal implements the fixed-point numbers used for crypto
+// quantities, prices and balances. Values are exact to 18 decimal places,
+// finer than any increment Robinhood quotes, and have no upper bound, so
+// SHIB-sized quantities and the totals computed from them don't drift the
+// way float64 does.
+package decimal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Places is the number of decimal places every value is stored with
+const Places = 18
+
+var (
+	scale = new(big.Int).Exp(big.NewInt(10), big.NewInt(Places), nil)
+
+	// Zero is the zero value, equal to Decimal{}
+	Zero = Decimal{}
+)
+
+// Decimal is an immutable fixed-point number. The zero value is 0.
+type Decimal struct {
+	units *big.Int // value × 10^Places; nil means zero
+}
+
+// New returns value × 10^exp, e.g. New(1, -8) is 0.00000001
+func New(value int64, exp int) Decimal {
+	if exp < -Places {
+		return fromRat(new(big.Rat).SetFrac(big.NewInt(value), pow10(-exp)))
+	}
+	units := new(big.Int).Mul(big.NewInt(value), pow10(Places+exp))
+	return Decimal{units: units}
+}
+
+// NewFromInt returns n as a Decimal
+func NewFromInt(n int64) Decimal {
+	return New(n, 0)
+}
+
+// NewFromFloat converts f using its shortest decimal representation, so
+// 0.1 becomes exactly 0.1. NaN and infinities become zero.
+func NewFromFloat(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Zero
+	}
+	d, err := Parse(strconv.FormatFloat(f, 'g', -1, 64))
+	if err != nil {
+		return Zero
+	}
+	return d
+}
+
+// plainDecimal matches the strings Parse accepts: an optional sign, digits
+// with an optional fraction and an optional exponent of up to four digits.
+// big.Rat alone would also read fractions, hex, binary and underscores.
+var plainDecimal = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]{1,4})?$`)
+
+// Parse reads a decimal string such as "0.00000950", "-12" or "1e-8".
+// Digits beyond Places are rounded half away from zero.
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if !plainDecimal.MatchString(s) {
+		return Zero, fmt.Errorf("invalid decimal %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Zero, fmt.Errorf("invalid decimal %q", s)
+	}
+	return fromRat(r), nil
+}
+
+// MustParse is like Parse but panics on invalid input. It is meant for constants.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func fromRat(r *big.Rat) Decimal {
+	units := divRound(new(big.Int).Mul(r.Num(), scale), r.Denom())
+	return Decimal{units: units}
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// divRound divides n by d, rounding half away from zero
+func divRound(n, d *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	if twice.Cmp(new(big.Int).Abs(d)) >= 0 {
+		if (n.Sign() < 0) != (d.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+func (d Decimal) int() *big.Int {
+	if d.units == nil {
+		return new(big.Int)
+	}
+	return d.units
+}
+
+// Add returns d + e
+func (d Decimal) Add(e Decimal) Decimal {
+	return Decimal{units: new(big.Int).Add(d.int(), e.int())}
+}
+
+// Sub returns d - e
+func (d Decimal) Sub(e Decimal) Decimal {
+	return Decimal{units: new(big.Int).Sub(d.int(), e.int())}
+}
+
+// Mul returns d × e, rounded to Places
+func (d Decimal) Mul(e Decimal) Decimal {
+	product := new(big.Int).Mul(d.int(), e.int())
+	return Decimal{units: divRound(product, scale)}
+}
+
+// Div returns d ÷ e, rounded to Places. Dividing by zero returns zero.
+func (d Decimal) Div(e Decimal) Decimal {
+	if e.IsZero() {
+		return Zero
+	}
+	scaled := new(big.Int).Mul(d.int(), scale)
+	return Decimal{units: divRound(scaled, e.int())}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{units: new(big.Int).Neg(d.int())}
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{units: new(big.Int).Abs(d.int())}
+}
+
+// Cmp returns -1, 0 or +1 as d is less than, equal to or greater than e
+func (d Decimal) Cmp(e Decimal) int {
+	return d.int().Cmp(e.int())
+}
+
+// Sign returns -1, 0 or +1 according to the sign of d
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// IsPositive reports whether d is greater than 0
+func (d Decimal) IsPositive() bool {
+	return d.Sign() > 0
+}
+
+// IsNegative reports whether d is less than 0
+func (d Decimal) IsNegative() bool {
+	return d.Sign() < 0
+}
+
+// Equal reports whether d and e are the same value
+func (d Decimal) Equal(e Decimal) bool {
+	return d.Cmp(e) == 0
+}
+
+// LessThan reports whether d < e
+func (d Decimal) LessThan(e Decimal) bool {
+	return d.Cmp(e) < 0
+}
+
+// GreaterThan reports whether d > e
+func (d Decimal) GreaterThan(e Decimal) bool {
+	return d.Cmp(e) > 0
+}
+
+// Max returns the larger of d and e
+func Max(d, e Decimal) Decimal {
+	if d.LessThan(e) {
+		return e
+	}
+	return d
+}
+
+// Min returns the smaller of d and e
+func Min(d, e Decimal) Decimal {
+	if d.GreaterThan(e) {
+		return e
+	}
+	return d
+}
+
+// Round rounds d to places decimal places, half away from zero
+func (d Decimal) Round(places int) Decimal {
+	if places >= Places {
+		return d
+	}
+	step := pow10(Places - places)
+	return Decimal{units: new(big.Int).Mul(divRound(d.int(), step), step)}
+}
+
+// Truncate drops the digits after places decimal places
+func (d Decimal) Truncate(places int) Decimal {
+	if places >= Places {
+		return d
+	}
+	step := pow10(Places - places)
+	return Decimal{units: new(big.Int).Mul(new(big.Int).Quo(d.int(), step), step)}
+}
+
+// RoundDownTo rounds d toward zero to a multiple of step, e.g. an order
+// quantity to the asset increment. A non-positive step leaves d unchanged.
+func (d Decimal) RoundDownTo(step Decimal) Decimal {
+	if !step.IsPositive() {
+		return d
+	}
+	steps := new(big.Int).Quo(d.int(), step.int())
+	return Decimal{units: steps.Mul(steps, step.int())}
+}
+
+// RoundTo rounds d to the nearest multiple of step, half away from zero.
+// A non-positive step leaves d unchanged.
+func (d Decimal) RoundTo(step Decimal) Decimal {
+	if !step.IsPositive() {
+		return d
+	}
+	steps := divRound(d.int(), step.int())
+	return Decimal{units: steps.Mul(steps, step.int())}
+}
+
+// IsMultipleOf reports whether d is a whole number of steps. Every value is
+// a multiple of a non-positive step.
+func (d Decimal) IsMultipleOf(step Decimal) bool {
+	if !step.IsPositive() {
+		return true
+	}
+	return new(big.Int).Rem(d.int(), step.int()).Sign() == 0
+}
+
+// Decimals returns the number of decimal places needed to show d exactly,
+// e.g. 2 for 0.01 and 8 for 0.00000001
+func (d Decimal) Decimals() int {
+	digits := new(big.Int).Abs(d.int()).String()
+	trailing := len(digits) - len(strings.TrimRight(digits, "0"))
+	if d.IsZero() || trailing >= Places {
+		return 0
+	}
+	return Places - trailing
+}
+
+// String formats d exactly, without trailing zeros, e.g. "0.0000095"
+func (d Decimal) String() string {
+	return d.StringFixed(d.Decimals())
+}
+
+// StringFixed formats d rounded to places decimal places, padding with
+// zeros, e.g. "43250.50"
+func (d Decimal) StringFixed(places int) string {
+	if places < 0 {
+		places = 0
+	}
+	if places > Places {
+		places = Places
+	}
+
+	units := d.Round(places).int()
+	digits := new(big.Int).Abs(units).String()
+	if len(digits) <= Places {
+		digits = strings.Repeat("0", Places-len(digits)+1) + digits
+	}
+
+	whole := digits[:len(digits)-Places]
+	fraction := digits[len(digits)-Places:][:places]
+
+	sign := ""
+	if units.Sign() < 0 {
+		sign = "-"
+	}
+	if places == 0 {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+// Float64 returns the nearest float64, for ratios and percentages that
+// don't need to be exact
+func (d Decimal) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(d.int(), scale).Float64()
+	return f
+}
+
+// MarshalJSON encodes d as a string, the way the API sends amounts
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a JSON number. Null and the
+// empty string decode to zero.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "" || text == "null" {
+		*d = Zero
+		return nil
+	}
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
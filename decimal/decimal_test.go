@@ -0,0 +1,55 @@
+package decimal
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"0.00000950", "0.0000095"},
+		{"-12", "-12"},
+		{"+3.5", "3.5"},
+		{"1e-8", "0.00000001"},
+		{"2.5E+3", "2500"},
+		{".5", "0.5"},
+		{"7.", "7"},
+		{" 42 ", "42"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejectsNonDecimals(t *testing.T) {
+	for _, in := range []string{
+		"",
+		" ",
+		"/",
+		"1/3",
+		"0x10",
+		"0b11",
+		"0o17",
+		"1_000",
+		"1e",
+		"e5",
+		".",
+		"--1",
+		"1.2.3",
+		"1e99999",
+		"NaN",
+		"Inf",
+		"12abc",
+	} {
+		if got, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %s, want an error", in, got)
+		}
+	}
+}
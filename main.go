@@ -1,19 +1,175 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"dazedtrader/api/cassette"
+	"dazedtrader/api/fake"
+	"dazedtrader/auth"
 	"dazedtrader/models"
+	"encoding/base64"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	model := models.NewAppModel()
+	if len(os.Args) > 1 {
+		if command, ok := subcommands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	os.Exit(run())
+}
+
+// run starts the UI and returns the exit code; returning rather than exiting
+// lets the deferred cleanup, such as flushing a recording, run first
+func run() int {
+	baseURL := flag.String("base-url", "", "Robinhood Crypto API base URL (default: production)")
+	useFake := flag.Bool("fake", false, "run against an in-process fake Robinhood Crypto server")
+	fakeAccounts := flag.Int("fake-accounts", 1, "number of accounts on the fake server, to try account switching (with -fake)")
+	fakeSkew := flag.Duration("fake-clock-skew", 0, "run the fake server's clock this far ahead of the local one (with -fake)")
+	record := flag.String("record", "", "record every HTTP exchange to this cassette file, without credentials")
+	replay := flag.String("replay", "", "replay a recorded cassette file instead of using the network")
+	logFile := flag.String("log", "", "append warnings, such as API response format changes, to this file as JSON")
+	keyFile := flag.String("key-file", "", "sign with this encrypted key file, unlocked with a passphrase at startup (see seal-key)")
+	signerCommand := flag.String("signer-command", "", "sign through this external signer command, run with the protocol on its stdin/stdout")
+	signerSocket := flag.String("signer-socket", "", "sign through an external signer listening on this Unix socket")
+	profile := flag.String("profile", auth.DefaultProfile, "use the stored API key of this named profile")
+	flag.Parse()
+
+	// Anything written to stderr would garble the full-screen UI
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	if *logFile != "" {
+		file, err := os.OpenFile(*logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			fmt.Printf("Error opening log file: %v", err)
+			return 1
+		}
+		defer file.Close()
+		slog.SetDefault(slog.New(slog.NewJSONHandler(file, nil)))
+	}
+
+	if *replay != "" && (*useFake || *record != "") {
+		fmt.Println("-replay cannot be combined with -fake or -record")
+		return 1
+	}
+
+	signers := 0
+	for _, option := range []string{*keyFile, *signerCommand, *signerSocket} {
+		if option != "" {
+			signers++
+		}
+	}
+	if signers > 1 {
+		fmt.Println("-key-file, -signer-command and -signer-socket are mutually exclusive")
+		return 1
+	}
+	if signers > 0 && (*useFake || *replay != "") {
+		fmt.Println("-key-file, -signer-command and -signer-socket cannot be combined with -fake or -replay")
+		return 1
+	}
+
+	if err := auth.ValidateProfileName(*profile); err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	settings, err := auth.LoadSettings()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	cfg := models.Config{BaseURL: *baseURL, Profile: *profile, Settings: settings}
+
+	if signers > 0 {
+		apiKey, signer, release, err := startSigner(*keyFile, *signerCommand, *signerSocket)
+		if err != nil {
+			fmt.Printf("Error setting up signer: %v", err)
+			return 1
+		}
+		defer release()
+		cfg.APIKey = apiKey
+		cfg.Signer = signer
+	}
+
+	if *useFake {
+		server := fake.NewServer()
+		defer server.Close()
+		server.SetClockOffset(*fakeSkew)
+		for i := 2; i <= *fakeAccounts; i++ {
+			server.AddAccount(fmt.Sprintf("FAKE%04d", i), 5000)
+		}
+
+		credentials, err := server.NewCredentials()
+		if err != nil {
+			fmt.Printf("Error starting fake server: %v", err)
+			return 1
+		}
+		cfg.BaseURL = server.URL
+		cfg.Credentials = credentials
+	}
+
+	if *record != "" {
+		recorder, err := cassette.NewRecorder(*record)
+		if err != nil {
+			fmt.Printf("Error starting recording: %v", err)
+			return 1
+		}
+		defer recorder.Close()
+		cfg.Transport = recorder
+	}
+
+	if *replay != "" {
+		player, err := cassette.NewPlayer(*replay)
+		if err != nil {
+			fmt.Printf("Error loading cassette: %v", err)
+			return 1
+		}
+
+		// The cassette holds no credentials; sign with a throwaway key so
+		// the client runs as if logged in
+		credentials, err := replayCredentials()
+		if err != nil {
+			fmt.Printf("Error loading cassette: %v", err)
+			return 1
+		}
+		cfg.Transport = player
+		cfg.Credentials = credentials
+
+		// Next page links are only followed on the API host, so replay as
+		// if talking to the host the cassette was recorded against
+		if *baseURL == "" {
+			cfg.BaseURL = player.Origin("/api/v1/crypto/")
+		}
+	}
+
+	model := models.NewAppModel(cfg)
 
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running program: %v", err)
-		os.Exit(1)
+		return 1
+	}
+
+	return 0
+}
+
+// replayCredentials generates a throwaway key in the "apikey:privatekey"
+// format for replay sessions
+func replayCredentials() (string, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate key pair: %w", err)
 	}
-}
\ No newline at end of file
+	return "replay:" + base64.StdEncoding.EncodeToString(privateKey), nil
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"dazedtrader/api"
+	"dazedtrader/ui"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// accountsLoadedMsg is sent once the account list has been refreshed
+type accountsLoadedMsg struct{ err error }
+
+// activeAccount returns the number of the account that holdings, orders and
+// new orders are scoped to, or "" before the accounts have been loaded
+func (m *AppModel) activeAccount() string {
+	if m.CryptoClient == nil {
+		return ""
+	}
+	return m.CryptoClient.AccountNumber
+}
+
+// useAccounts stores the account list and makes sure the active account is
+// one of them, falling back to the first. It returns the active account.
+func (m *AppModel) useAccounts(accounts []api.CryptoAccount) *api.CryptoAccount {
+	m.Accounts = accounts
+	if len(accounts) == 0 {
+		return nil
+	}
+	for i := range accounts {
+		if accounts[i].AccountNumber == m.CryptoClient.AccountNumber {
+			return &accounts[i]
+		}
+	}
+	m.CryptoClient.AccountNumber = accounts[0].AccountNumber
+	return &accounts[0]
+}
+
+// openAccountPicker shows the account list with the active account selected,
+// returning to the current screen afterwards
+func (m *AppModel) openAccountPicker() tea.Cmd {
+	if !m.Authenticated || m.CryptoClient == nil {
+		return nil
+	}
+
+	m.AccountReturnState = m.State
+	m.AccountCursor = 0
+	for i, account := range m.Accounts {
+		if account.AccountNumber == m.activeAccount() {
+			m.AccountCursor = i
+		}
+	}
+	m.Error = ""
+	m.State = StateAccounts
+	return m.loadAccountsCmd()
+}
+
+// LoadAccounts refreshes the list of accounts the API key can trade
+func (m *AppModel) LoadAccounts() error {
+	if !m.Authenticated || m.CryptoClient == nil {
+		return nil
+	}
+
+	m.LoadingAccounts = true
+	defer func() {
+		m.LoadingAccounts = false
+	}()
+
+	ctx := m.requestContext()
+	accounts, err := m.CryptoClient.GetCryptoAccountsContext(ctx)
+	if err != nil && ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		m.Error = describeAPIError("Failed to load accounts", err)
+		return err
+	}
+
+	m.useAccounts(accounts)
+	if m.AccountCursor >= len(accounts) {
+		m.AccountCursor = 0
+	}
+	return nil
+}
+
+func (m *AppModel) loadAccountsCmd() tea.Cmd {
+	return func() tea.Msg {
+		err := m.LoadAccounts()
+		return accountsLoadedMsg{err: err}
+	}
+}
+
+func (m *AppModel) handleAccountKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.AccountCursor > 0 {
+			m.AccountCursor--
+		}
+	case "down", "j":
+		if m.AccountCursor < len(m.Accounts)-1 {
+			m.AccountCursor++
+		}
+	case "enter":
+		// Wait for the list and any portfolio refresh so nothing from the
+		// previous account lands after the switch
+		if m.LoadingAccounts || m.Loading || m.AccountCursor >= len(m.Accounts) {
+			return m, nil
+		}
+		return m, m.switchAccount(m.Accounts[m.AccountCursor].AccountNumber)
+	}
+	return m, nil
+}
+
+// switchAccount makes number the active account, drops everything loaded for
+// the previous one and reloads the screen the picker was opened from
+func (m *AppModel) switchAccount(number string) tea.Cmd {
+	m.State = m.AccountReturnState
+	if number == m.activeAccount() {
+		return nil
+	}
+
+	m.CryptoClient.AccountNumber = number
+	m.Portfolio = nil
+	m.OrderPages = nil
+	m.OlderOrders = nil
+	m.FilteredOrders = nil
+	m.FilterPages = nil
+	m.OrderCursor = 0
+	m.DetailOrderID = ""
+	m.DetailOrder = nil
+	m.DetailQuote = nil
+
+	if m.orderFilterActive() {
+		return tea.Batch(m.loadCryptoPortfolioCmd(), m.loadFilteredOrdersCmd())
+	}
+	return m.loadCryptoPortfolioCmd()
+}
+
+// accountStatus names the active account, with a hint to switch when the
+// API key can trade more than one
+func (m *AppModel) accountStatus() string {
+	active := m.activeAccount()
+	if active == "" {
+		return ""
+	}
+	if len(m.Accounts) < 2 {
+		return ui.InfoStyle.Render("🏦 Account: "+active) + "\n\n"
+	}
+	return ui.InfoStyle.Render(fmt.Sprintf("🏦 Account: %s (%d accounts, 'A' to switch)", active, len(m.Accounts))) + "\n\n"
+}
+
+// accountsView renders the account picker
+func (m *AppModel) accountsView() string {
+	title := ui.HeaderStyle.Render("🏦 ACCOUNTS")
+	footer := ui.InfoStyle.Render("↑↓ select • Enter to switch • 'Esc' to go back")
+
+	var content strings.Builder
+
+	if m.Error != "" {
+		content.WriteString(ui.NegativeStyle.Render("❌ " + m.Error + "\n\n"))
+	}
+
+	if len(m.Accounts) == 0 {
+		if m.LoadingAccounts {
+			content.WriteString(ui.LoadingStyle.Render("🔄 Loading accounts...\n"))
+		} else {
+			content.WriteString("📊 No accounts found for this API key.\n")
+		}
+		return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
+	}
+
+	content.WriteString("Holdings, order history and new orders use the active account.\n\n")
+	content.WriteString("  Account          Status      Buying Power\n")
+	content.WriteString("─────────────────────────────────────────────────\n")
+
+	for i, account := range m.Accounts {
+		cursor := "  "
+		if i == m.AccountCursor {
+			cursor = "► "
+		}
+		line := fmt.Sprintf("%s%-15s  %-10s  %s", cursor, account.AccountNumber, account.Status, ui.FormatValue(account.BuyingPower))
+		if account.AccountNumber == m.activeAccount() {
+			line = ui.PositiveStyle.Render(line + "  (active)")
+		}
+		content.WriteString(line + "\n")
+	}
+
+	if m.LoadingAccounts {
+		content.WriteString("\n" + ui.LoadingStyle.Render("🔄 Refreshing accounts...") + "\n")
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
+}
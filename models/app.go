@@ -1,12 +1,15 @@
 package models
 
 import (
+	"context"
 	"dazedtrader/api"
 	"dazedtrader/auth"
+	"dazedtrader/decimal"
+	"dazedtrader/ui"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +31,19 @@ type AppModel struct {
 	NewsData      *NewsData
 	Error         string
 	DataSource    string // Track where pricing data comes from
+	BaseURL       string // Robinhood API host override, empty for production
+
+	// Set when credentials came from Config; they are never saved or cleared
+	ephemeralCredentials bool
+
+	// Profile names the API key in use, kept in the credential store the
+	// settings select for it
+	Profile     string
+	settings    *auth.Settings
+	credentials auth.CredentialStore
+
+	// Carries every HTTP request the app makes; nil means the default transport
+	transport http.RoundTripper
 	Loading       bool
 	NewsPage      int    // Current news page for pagination
 
@@ -35,6 +51,17 @@ type AppModel struct {
 	APIKeyForm   APIKeyForm
 	ShowAPIKey   bool
 
+	// Passphrase screen for unlocking or encrypting the stored API key;
+	// LoadingKey is set while the key is read from its credential store
+	Passphrase PassphraseForm
+	LoadingKey bool
+
+	// Profile picker, opened from the menu
+	ProfilePicker ProfilePicker
+
+	// Key pair generated for a new API key, opened from the login screen
+	KeyGen KeyGen
+
 	// Trading state
 	TradingForm  TradingForm
 	TradingStep  int
@@ -42,17 +69,87 @@ type AppModel struct {
 	// Token price change cache
 	TokenPriceCache map[string]float64
 	TokenCacheTime  time.Time
+
+	// Order rules per symbol, loaded once after login
+	TradingPairs map[string]api.TradingPair
+
+	// Order history paging: OrderPages continues after the first page
+	// loaded with the portfolio, OlderOrders holds the pages loaded since
+	OrderPages    *api.Paginator[api.CryptoOrder]
+	OlderOrders   []CryptoOrder
+	LoadingOrders bool
+
+	// Order history filter: while OrderFilter is set the history shows
+	// FilteredOrders, fetched server-side and paged through FilterPages
+	OrderFilter    api.OrderFilter
+	FilterForm     OrderFilterForm
+	FilteredOrders []CryptoOrder
+	FilterPages    *api.Paginator[api.CryptoOrder]
+	EditingFilter  bool
+
+	// Order history selection and the order detail screen: DetailOrder is
+	// fetched with its executions, DetailQuote prices the spread estimate
+	OrderCursor   int
+	DetailOrderID string
+	DetailOrder   *api.CryptoOrder
+	DetailQuote   *api.BestBidAsk
+	LoadingDetail bool
+
+	// Accounts the API key can trade; the active one is the client's
+	// AccountNumber. The picker returns to AccountReturnState.
+	Accounts           []api.CryptoAccount
+	AccountCursor      int
+	AccountReturnState int
+	LoadingAccounts    bool
+
+	// Bulk cancel screen, opened from the order history
+	BulkCancel BulkCancel
+
+	// Orders recorded before they were sent whose outcome isn't confirmed
+	// yet; submittingIntent is the one being sent right now
+	intents          *auth.IntentStore
+	submittingIntent string
+
+	// Order submitted from the trading screen that is still being polled,
+	// and the outcome of the last tracked order
+	TrackedOrder *OrderTracker
+	Notice       string
+
+	// Cancels the API calls started on behalf of the current screen
+	requestCtx    context.Context
+	cancelRequest context.CancelFunc
+
+	// Refresh tick loop that is live; ticks of older loops are dropped
+	tickLoop int
 }
 
+
 type TradingForm struct {
-	Symbol       string
-	Side         string  // "buy" or "sell"
-	Type         string  // "market" or "limit"
-	Quantity     string
-	Price        string
-	CurrentPrice float64 // Live price for the symbol
-	EstimatedCost float64 // Estimated total cost
-	Submitting   bool
+	Symbol        string
+	Side          string // "buy" or "sell"
+	Type          string // "market" or "limit"
+	Quantity      string
+	Price         string // Limit price
+	StopPrice     string
+	TimeInForce   string          // "gtc" or "gfd"
+	CurrentPrice  decimal.Decimal // Live price for the symbol
+	EstimatedCost decimal.Decimal // Estimated total cost
+	Submitting    bool
+
+	// QuoteAmount means Quantity holds a USD amount rather than an asset
+	// quantity; EstimatedQuantity is the asset quantity it should buy or sell
+	QuoteAmount       bool
+	EstimatedQuantity decimal.Decimal
+
+	// Execution quote from the estimated_price endpoint, valid while the
+	// symbol, side and quantity still match QuotedFor
+	QuotedFor   string
+	QuotedPrice decimal.Decimal // Expected fill price including the spread
+	SpreadCost  decimal.Decimal // Amount paid to the spread versus the mid price
+
+	// Intent of the last submission whose outcome is unknown; submitting
+	// the same order again reuses its client_order_id
+	Intent *auth.OrderIntent
 }
 
 type APIKeyForm struct {
@@ -60,7 +157,7 @@ type APIKeyForm struct {
 }
 
 type CryptoPortfolio struct {
-	BuyingPower     float64
+	BuyingPower     decimal.Decimal
 	BuyingPowerCurrency string
 	Holdings        []CryptoPosition
 	Orders          []CryptoOrder
@@ -70,12 +167,12 @@ type CryptoPortfolio struct {
 type CryptoPosition struct {
 	AssetCode       string
 	AssetName       string
-	Quantity        float64
-	QuantityAvail   float64
-	CostBasis       float64
-	MarketValue     float64
-	CurrentPrice    float64
-	DayChange       float64
+	Quantity        decimal.Decimal
+	QuantityAvail   decimal.Decimal
+	CostBasis       decimal.Decimal
+	MarketValue     decimal.Decimal
+	CurrentPrice    decimal.Decimal
+	DayChange       decimal.Decimal
 	PercentChange   float64
 }
 
@@ -87,8 +184,8 @@ type CryptoOrder struct {
 	Side            string
 	Type            string
 	State           string
-	AveragePrice    float64
-	FilledQuantity  float64
+	AveragePrice    decimal.Decimal
+	FilledQuantity  decimal.Decimal
 	CreatedAt       string
 	UpdatedAt       string
 }
@@ -102,8 +199,8 @@ type MarketData struct {
 type CryptoMarketInfo struct {
 	Symbol          string
 	Name            string
-	Price           float64
-	Change24h       float64
+	Price           decimal.Decimal
+	Change24h       decimal.Decimal
 	ChangePercent24h float64
 	Volume24h       float64
 	MarketCap       float64
@@ -127,26 +224,36 @@ type NewsArticle struct {
 	Symbols     []string // Related crypto symbols
 }
 
-func NewAppModel() *AppModel {
-	// Try to load existing API key
-	apiKeyData, err := auth.LoadAPIKey()
-	authenticated := false
-	username := ""
-	var cryptoClient *api.CryptoClient
+// Config holds the startup options for the application model
+type Config struct {
+	// BaseURL points the API client at another host, e.g. a sandbox or the
+	// in-process fake server. Empty means production.
+	BaseURL string
 
-	if err == nil && apiKeyData != nil {
-		// Check if API key is still valid (assume 30 day expiry)
-		if time.Now().Unix() < apiKeyData.ExpiresAt {
-			cryptoClient = api.NewCryptoClient(apiKeyData.APIKey)
-			authenticated = true
-			username = apiKeyData.Username
-		} else {
-			// API key expired, clear it
-			auth.ClearAPIKey()
-		}
-	}
+	// Credentials ("apikey:privatekey") to use instead of the stored API key.
+	// They are never written to disk.
+	Credentials string
+
+	// Transport carries the Robinhood, CoinGecko and news requests, e.g. a
+	// cassette recorder or player. Nil means http.DefaultTransport.
+	Transport http.RoundTripper
 
-	return &AppModel{
+	// Profile names the stored API key to use; empty means the default one
+	Profile string
+
+	// Settings select the credential store of each profile; nil keeps every
+	// key in its encrypted file
+	Settings *auth.Settings
+
+	// Signer signs requests for APIKey in place of a private key, e.g. an
+	// unlocked key file or an external signer process. It takes precedence
+	// over Credentials and is never written to disk.
+	Signer api.Signer
+	APIKey string
+}
+
+func NewAppModel(cfg Config) *AppModel {
+	m := &AppModel{
 		State: StateMenu,
 		Choices: []string{
 			"₿ Crypto Portfolio",
@@ -159,11 +266,83 @@ func NewAppModel() *AppModel {
 			"🔓 Logout",
 			"🚪 Exit",
 		},
-		Cursor:        0,
-		Authenticated: authenticated,
-		Username:      username,
-		CryptoClient:  cryptoClient,
+		Cursor:    0,
+		BaseURL:   cfg.BaseURL,
+		transport: cfg.Transport,
+		Profile:   cfg.Profile,
+		settings:  cfg.Settings,
+	}
+	if m.Profile == "" {
+		m.Profile = auth.DefaultProfile
+	}
+
+	// Ephemeral sessions never write pending orders to disk either
+	if cfg.Credentials != "" || cfg.Signer != nil {
+		m.intents = auth.NewMemoryIntentStore()
+	} else if store, err := auth.OpenIntentStore(); err == nil {
+		m.intents = store
+	} else {
+		m.intents = auth.NewMemoryIntentStore()
+		m.Notice = fmt.Sprintf("⚠ Pending orders could not be loaded, earlier orders with unknown outcome won't be checked: %v", err)
+	}
+
+	if cfg.Signer != nil {
+		m.CryptoClient = m.configureClient(api.NewCryptoClientWithSigner(cfg.APIKey, cfg.Signer))
+		m.Authenticated = true
+		m.Username = "Crypto Trader"
+		m.ephemeralCredentials = true
+		return m
 	}
+
+	if cfg.Credentials != "" {
+		m.CryptoClient = m.newCryptoClient(cfg.Credentials)
+		m.Authenticated = m.CryptoClient != nil
+		m.Username = "Crypto Trader"
+		m.ephemeralCredentials = true
+		return m
+	}
+
+	// A stored API key is unlocked with its passphrase before logging in
+	if err := m.useProfile(m.Profile); err != nil {
+		m.useProfile(auth.DefaultProfile)
+	}
+
+	return m
+}
+
+// newCryptoClient creates an API client for the given credentials, pointed
+// at the configured base URL
+func (m *AppModel) newCryptoClient(credentials string) *api.CryptoClient {
+	client := api.NewCryptoClient(credentials)
+	if client == nil {
+		return nil
+	}
+	return m.configureClient(client)
+}
+
+// configureClient points client at the configured base URL and transport
+func (m *AppModel) configureClient(client *api.CryptoClient) *api.CryptoClient {
+	if m.BaseURL != "" {
+		client.SetBaseURL(m.BaseURL)
+	}
+	client.HTTPClient.Transport = m.transport
+	return client
+}
+
+// httpClient returns a client for the market data and news services that
+// uses the configured transport
+func (m *AppModel) httpClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: m.transport}
+}
+
+// httpGet fetches url from a market data or news service, aborting when the
+// current screen's requests are cancelled
+func (m *AppModel) httpGet(url string, timeout time.Duration) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(m.requestContext(), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return m.httpClient(timeout).Do(req)
 }
 
 // App states
@@ -177,6 +356,12 @@ const (
 	StateOrderHistory
 	StateNews
 	StateHelp
+	StateOrderDetail
+	StateAccounts
+	StateBulkCancel
+	StatePassphrase
+	StateProfiles
+	StateKeyGen
 )
 
 // Trading steps
@@ -187,8 +372,83 @@ const (
 	TradingStepQuantity
 	TradingStepPrice
 	TradingStepConfirm
+	TradingStepStopPrice
+	TradingStepTimeInForce
 )
 
+// orderTypes lists the order types offered by the trading wizard, in display order
+var orderTypes = []struct {
+	Type  string
+	Label string
+}{
+	{"market", "MARKET (Execute immediately at current price)"},
+	{"limit", "LIMIT (Set your own price)"},
+	{"stop_loss", "STOP LOSS (Market order once the stop price is reached)"},
+	{"stop_limit", "STOP LIMIT (Limit order once the stop price is reached)"},
+}
+
+// orderTypeName formats an API order type for display, e.g. "STOP LOSS"
+func orderTypeName(orderType string) string {
+	return strings.ToUpper(strings.ReplaceAll(orderType, "_", " "))
+}
+
+// usesLimitPrice reports whether the order type takes a limit price
+func (f TradingForm) usesLimitPrice() bool {
+	return f.Type == "limit" || f.Type == "stop_limit"
+}
+
+// usesStopPrice reports whether the order type takes a stop price
+func (f TradingForm) usesStopPrice() bool {
+	return f.Type == "stop_loss" || f.Type == "stop_limit"
+}
+
+// tradingSteps returns the wizard steps for the form's order type, in order
+func (f TradingForm) tradingSteps() []int {
+	steps := []int{TradingStepSymbol, TradingStepSide, TradingStepType, TradingStepQuantity}
+	if f.usesStopPrice() {
+		steps = append(steps, TradingStepStopPrice)
+	}
+	if f.usesLimitPrice() {
+		steps = append(steps, TradingStepPrice)
+	}
+	if f.Type != "market" {
+		steps = append(steps, TradingStepTimeInForce)
+	}
+	return append(steps, TradingStepConfirm)
+}
+
+// tradingStepNumber returns the 1-based position of step in the wizard
+func (m *AppModel) tradingStepNumber(step int) int {
+	for i, s := range m.TradingForm.tradingSteps() {
+		if s == step {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// nextTradingStep advances the wizard to the step after the current one
+func (m *AppModel) nextTradingStep() {
+	steps := m.TradingForm.tradingSteps()
+	for i, s := range steps {
+		if s == m.TradingStep && i+1 < len(steps) {
+			m.TradingStep = steps[i+1]
+			return
+		}
+	}
+}
+
+// previousTradingStep moves the wizard back to the step before the current one
+func (m *AppModel) previousTradingStep() {
+	steps := m.TradingForm.tradingSteps()
+	for i, s := range steps {
+		if s == m.TradingStep && i > 0 {
+			m.TradingStep = steps[i-1]
+			return
+		}
+	}
+}
+
 // LoadCryptoPortfolio loads real crypto portfolio data from Robinhood Crypto API
 func (m *AppModel) LoadCryptoPortfolio() error {
 	if !m.Authenticated || m.Loading || m.CryptoClient == nil {
@@ -202,21 +462,33 @@ func (m *AppModel) LoadCryptoPortfolio() error {
 		m.Loading = false
 	}()
 
-	// Get crypto account
-	account, err := m.CryptoClient.GetCryptoAccount()
+	ctx := m.requestContext()
+
+	// Get crypto accounts; holdings and orders below are scoped to the
+	// active one
+	accounts, err := m.CryptoClient.GetCryptoAccountsContext(ctx)
 	if err != nil {
-		m.Error = fmt.Sprintf("Failed to get crypto account: %v", err)
+		if ctx.Err() != nil {
+			return nil // Screen was left, nothing to report
+		}
+		m.Error = describeAPIError("Failed to get crypto account", err)
 		return err
 	}
+	account := m.useAccounts(accounts)
+	if account == nil {
+		m.Error = "No crypto account found for this API key"
+		return fmt.Errorf("no crypto account found")
+	}
 
-
-	// Parse account values
-	buyingPower, _ := strconv.ParseFloat(account.BuyingPower, 64)
+	buyingPower := account.BuyingPower
 
 	// Get crypto holdings
-	holdings, err := m.CryptoClient.GetCryptoHoldings()
+	holdings, err := m.CryptoClient.GetCryptoHoldingsContext(ctx)
 	if err != nil {
-		m.Error = fmt.Sprintf("Failed to get crypto holdings: %v", err)
+		if ctx.Err() != nil {
+			return nil
+		}
+		m.Error = describeAPIError("Failed to get crypto holdings", err)
 		return err
 	}
 
@@ -243,8 +515,9 @@ func (m *AppModel) LoadCryptoPortfolio() error {
 	}
 
 	// Get recent crypto orders first (before prices)
-	// Use the new function with better field handling
-	orders, err := m.CryptoClient.GetCryptoOrdersWithParams(20) // Get up to 20 orders
+	// Keep the paginator around so the order history screen can load older pages
+	orderPages := m.CryptoClient.CryptoOrdersPages(api.OrderFilter{Limit: 20})
+	orders, err := orderPages.NextContext(ctx) // Get up to 20 orders
 	var portfolioOrders []CryptoOrder
 	if err == nil {
 		maxOrders := 20
@@ -253,24 +526,18 @@ func (m *AppModel) LoadCryptoPortfolio() error {
 		}
 
 		for i := 0; i < maxOrders; i++ {
-			order := orders[i]
-			portfolioOrders = append(portfolioOrders, CryptoOrder{
-				ID:             order.ID,
-				AccountNumber:  order.AccountNumber,
-				Symbol:         order.Symbol,
-				ClientOrderID:  order.ClientOrderID,
-				Side:           order.Side,
-				Type:           order.Type,
-				State:          order.State,
-				AveragePrice:   order.AveragePrice,
-				FilledQuantity: order.FilledAssetQuantity,
-				CreatedAt:      order.CreatedAt,
-				UpdatedAt:      order.UpdatedAt,
-			})
+			portfolioOrders = append(portfolioOrders, convertCryptoOrder(orders[i]))
+		}
+
+		// Only move the cursor while no older pages are on screen, otherwise
+		// the next page would no longer line up with what was already loaded
+		if len(m.OlderOrders) == 0 {
+			m.OrderPages = orderPages
 		}
 	} else {
-		// Try the original method as fallback
-		orders, err = m.CryptoClient.GetCryptoOrders()
+		// Try the original method as fallback, stopping once there are
+		// enough orders to show rather than paging through the whole history
+		orders, err = m.CryptoClient.GetAllCryptoOrdersContext(ctx, 10)
 		if err == nil {
 			maxOrders := 10
 			if len(orders) < maxOrders {
@@ -278,42 +545,21 @@ func (m *AppModel) LoadCryptoPortfolio() error {
 			}
 
 			for i := 0; i < maxOrders; i++ {
-				order := orders[i]
-				portfolioOrders = append(portfolioOrders, CryptoOrder{
-					ID:             order.ID,
-					AccountNumber:  order.AccountNumber,
-					Symbol:         order.Symbol,
-					ClientOrderID:  order.ClientOrderID,
-					Side:           order.Side,
-					Type:           order.Type,
-					State:          order.State,
-					AveragePrice:   order.AveragePrice,
-					FilledQuantity: order.FilledAssetQuantity,
-					CreatedAt:      order.CreatedAt,
-					UpdatedAt:      order.UpdatedAt,
-				})
+				portfolioOrders = append(portfolioOrders, convertCryptoOrder(orders[i]))
 			}
 		}
 	}
 
+	// Settle orders left with an unknown outcome by an earlier submission
+	m.reconcileOrderIntents(ctx)
+
 	// Get current live prices from Robinhood API and calculate market values
-	var totalDayChange float64 = 0
+	totalDayChange := decimal.Zero
 	if len(symbols) > 0 {
-		// Fetch prices one symbol at a time to avoid JSON truncation
-		var allQuotes []api.BestBidAsk
-
-		for _, symbol := range symbols {
-			quotes, err := m.CryptoClient.GetBestBidAsk([]string{symbol})
-			if err != nil {
-				continue // Try next symbol
-			}
-
-			if len(quotes) > 0 {
-				allQuotes = append(allQuotes, quotes[0])
-			}
-
-			// Small delay to avoid overwhelming the API
-			time.Sleep(100 * time.Millisecond)
+		// Fetch all holding prices in batched requests
+		allQuotes, _ := m.CryptoClient.GetBestBidAskContext(ctx, symbols)
+		if ctx.Err() != nil {
+			return nil
 		}
 
 		if len(allQuotes) == 0 {
@@ -336,14 +582,14 @@ func (m *AppModel) LoadCryptoPortfolio() error {
 				pos := &portfolioPositions[i]
 				symbol := pos.AssetCode + "-USD"
 
-				if price, exists := fallbackPrices[symbol]; exists && price > 0 {
+				if price, exists := fallbackPrices[symbol]; exists && price.IsPositive() {
 					pos.CurrentPrice = price
-					pos.MarketValue = pos.Quantity * price
+					pos.MarketValue = pos.Quantity.Mul(price)
 					// Simulate 3% daily gain for demo (in production would use historical data)
-					yesterdayPrice := price * 0.97
-					pos.DayChange = (price - yesterdayPrice) * pos.Quantity
+					yesterdayPrice := price.Mul(decimal.MustParse("0.97"))
+					pos.DayChange = price.Sub(yesterdayPrice).Mul(pos.Quantity)
 					pos.PercentChange = 3.0
-					totalDayChange += pos.DayChange
+					totalDayChange = totalDayChange.Add(pos.DayChange)
 				}
 			}
 
@@ -377,28 +623,26 @@ func (m *AppModel) LoadCryptoPortfolio() error {
 
 			if quote, exists := quoteMap[symbol]; exists {
 				// Get current price from API
-				var currentPrice float64
-				if quote.Price > 0 {
+				var currentPrice decimal.Decimal
+				if quote.Price.IsPositive() {
 					currentPrice = quote.Price
-				} else if quote.BidPrice > 0 && quote.AskPrice > 0 {
-					currentPrice = (quote.BidPrice + quote.AskPrice) / 2
+				} else if quote.BidPrice.IsPositive() && quote.AskPrice.IsPositive() {
+					currentPrice = quote.BidPrice.Add(quote.AskPrice).Div(decimal.NewFromInt(2))
 				}
 
-				if currentPrice > 0 {
+				if currentPrice.IsPositive() {
 					pos.CurrentPrice = currentPrice
-					pos.MarketValue = pos.Quantity * currentPrice
+					pos.MarketValue = pos.Quantity.Mul(currentPrice)
 
 					// For day change, we'd need historical price data
 					// Since Robinhood API doesn't provide this in these endpoints,
 					// we'll simulate realistic daily changes for demo
 					// In production, you'd store previous day's prices or use external data
-					yesterdayPrice := currentPrice * (1 - 0.03) // Assume 3% gain for demo
-					pos.DayChange = (currentPrice - yesterdayPrice) * pos.Quantity
-					if currentPrice > 0 {
-						pos.PercentChange = ((currentPrice - yesterdayPrice) / yesterdayPrice) * 100
-					}
+					yesterdayPrice := currentPrice.Mul(decimal.MustParse("0.97")) // Assume 3% gain for demo
+					pos.DayChange = currentPrice.Sub(yesterdayPrice).Mul(pos.Quantity)
+					pos.PercentChange = currentPrice.Sub(yesterdayPrice).Div(yesterdayPrice).Float64() * 100
 
-					totalDayChange += pos.DayChange
+					totalDayChange = totalDayChange.Add(pos.DayChange)
 				}
 			}
 		}
@@ -407,9 +651,9 @@ func (m *AppModel) LoadCryptoPortfolio() error {
 	// Orders already fetched earlier
 
 	// Calculate total portfolio value for display
-	totalValue := 0.0
+	totalValue := decimal.Zero
 	for _, pos := range portfolioPositions {
-		totalValue += pos.MarketValue
+		totalValue = totalValue.Add(pos.MarketValue)
 	}
 
 	// Update crypto portfolio
@@ -427,9 +671,78 @@ func (m *AppModel) LoadCryptoPortfolio() error {
 	return nil
 }
 
+// convertCryptoOrder maps an API order onto the model used by the views
+func convertCryptoOrder(order api.CryptoOrder) CryptoOrder {
+	// Show the ordered size for orders that have not filled yet
+	filled := order.FilledAssetQuantity
+	if filled.IsZero() {
+		filled = order.Quantity
+	}
+
+	return CryptoOrder{
+		ID:             order.ID,
+		AccountNumber:  order.AccountNumber,
+		Symbol:         order.Symbol,
+		ClientOrderID:  order.ClientOrderID,
+		Side:           order.Side,
+		Type:           order.Type,
+		State:          order.State,
+		AveragePrice:   order.AveragePrice,
+		FilledQuantity: filled,
+		CreatedAt:      order.CreatedAt,
+		UpdatedAt:      order.UpdatedAt,
+	}
+}
+
+// LoadOlderOrders fetches the next page of order history and appends it to
+// OlderOrders, or to FilteredOrders while a filter is active
+func (m *AppModel) LoadOlderOrders() error {
+	pages, shown := m.OrderPages, &m.OlderOrders
+	if m.orderFilterActive() {
+		pages, shown = m.FilterPages, &m.FilteredOrders
+	}
+	if !m.Authenticated || m.CryptoClient == nil || pages == nil || m.LoadingOrders {
+		return nil
+	}
+
+	m.LoadingOrders = true
+	defer func() {
+		m.LoadingOrders = false
+	}()
+
+	ctx := m.requestContext()
+	orders, err := pages.NextContext(ctx)
+	if err != nil && ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		m.Error = describeAPIError("Failed to load older orders", err)
+		return err
+	}
+
+	// Skip anything already shown (e.g. orders that shifted pages)
+	seen := make(map[string]bool)
+	if m.Portfolio != nil && !m.orderFilterActive() {
+		for _, order := range m.Portfolio.Orders {
+			seen[order.ID] = true
+		}
+	}
+	for _, order := range *shown {
+		seen[order.ID] = true
+	}
+
+	for _, order := range orders {
+		if !seen[order.ID] {
+			*shown = append(*shown, convertCryptoOrder(order))
+		}
+	}
+
+	return nil
+}
+
 // getLiveFallbackPrices fetches live prices from CoinGecko API when Robinhood API fails
-func (m *AppModel) getLiveFallbackPrices(symbols []string) map[string]float64 {
-	fallbackPrices := make(map[string]float64)
+func (m *AppModel) getLiveFallbackPrices(symbols []string) map[string]decimal.Decimal {
+	fallbackPrices := make(map[string]decimal.Decimal)
 
 	// Map crypto symbols to CoinGecko IDs
 	symbolToCoinID := map[string]string{
@@ -492,8 +805,7 @@ func (m *AppModel) getLiveFallbackPrices(symbols []string) map[string]float64 {
 	coinIDsStr := strings.Join(coinIDs, ",")
 	url := fmt.Sprintf("https://api.coingecko.com/api/v3/simple/price?ids=%s&vs_currencies=usd", coinIDsStr)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := m.httpGet(url, 10*time.Second)
 	if err != nil {
 		return fallbackPrices
 	}
@@ -513,7 +825,7 @@ func (m *AppModel) getLiveFallbackPrices(symbols []string) map[string]float64 {
 	for symbol, coinID := range symbolToID {
 		if priceData, exists := geckoResponse[coinID]; exists {
 			if price, priceExists := priceData["usd"]; priceExists && price > 0 {
-				fallbackPrices[symbol+"-USD"] = price
+				fallbackPrices[symbol+"-USD"] = decimal.NewFromFloat(price)
 			}
 		}
 	}
@@ -547,17 +859,12 @@ func (m *AppModel) LoadMarketData() error {
 		"AAVE-USD", "SUSHI-USD", "QTUM-USD", "DASH-USD", "NEO-USD",
 	}
 
-	// Fetch real-time prices from Robinhood API (one at a time to avoid JSON truncation)
-	var quotes []api.BestBidAsk
-	for _, symbol := range symbols {
-		symbolQuotes, err := m.CryptoClient.GetBestBidAsk([]string{symbol})
-		if err != nil {
-			continue // Skip failed symbols
-		}
-		if len(symbolQuotes) > 0 {
-			quotes = append(quotes, symbolQuotes[0])
-		}
-		time.Sleep(50 * time.Millisecond) // Small delay between requests
+	ctx := m.requestContext()
+
+	// Fetch real-time prices from Robinhood API in batched requests
+	quotes, _ := m.CryptoClient.GetBestBidAskContext(ctx, symbols)
+	if ctx.Err() != nil {
+		return nil // Screen was left, skip the fallbacks too
 	}
 
 	// If Robinhood API fails completely, use CoinGecko as fallback
@@ -574,10 +881,11 @@ func (m *AppModel) LoadMarketData() error {
 	var allCryptos []CryptoMarketInfo
 
 	for _, quote := range quotes {
-		if quote.Price <= 0 {
+		if !quote.Price.IsPositive() {
 			// Skip if no valid price
 			continue
 		}
+		price := quote.Price.Float64()
 
 		// Extract symbol name (remove -USD suffix)
 		symbol := strings.Replace(quote.Symbol, "-USD", "", 1)
@@ -585,19 +893,19 @@ func (m *AppModel) LoadMarketData() error {
 		// For demo purposes, simulate 24h changes based on price ranges
 		// In production, this would come from historical price data
 		var changePercent24h float64
-		if quote.Price > 50000 { // High-value coins like BTC
+		if price > 50000 { // High-value coins like BTC
 			changePercent24h = (float64(len(symbol)*3) - 15) * 0.8 // Range: ~-6% to +6%
-		} else if quote.Price > 1000 { // Mid-value coins like ETH
+		} else if price > 1000 { // Mid-value coins like ETH
 			changePercent24h = (float64(len(symbol)*4) - 20) * 0.6 // Range: ~-8% to +4%
 		} else { // Lower-value coins
 			changePercent24h = (float64(len(symbol)*5) - 25) * 0.4 // Range: ~-10% to +5%
 		}
 
-		change24h := quote.Price * changePercent24h / 100
+		change24h := quote.Price.Mul(decimal.NewFromFloat(changePercent24h)).Div(decimal.NewFromInt(100))
 
 		// Simulate volume and market cap based on price and popularity
-		volume24h := quote.Price * float64(1000000+len(symbol)*50000000)
-		marketCap := quote.Price * float64(10000000+len(symbol)*100000000)
+		volume24h := price * float64(1000000+len(symbol)*50000000)
+		marketCap := price * float64(10000000+len(symbol)*100000000)
 
 		crypto := CryptoMarketInfo{
 			Symbol:           symbol,
@@ -685,8 +993,7 @@ func (m *AppModel) loadMarketDataFromCoinGecko(symbols []string) error {
 	coinIDsStr := strings.Join(coinIDs, ",")
 	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&ids=%s&order=market_cap_desc&per_page=50&page=1&sparkline=false&price_change_percentage=24h", coinIDsStr)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := m.httpGet(url, 10*time.Second)
 	if err != nil {
 		// If CoinGecko fails, use backup data
 		m.DataSource = "Backup Data (CoinGecko unavailable)"
@@ -722,8 +1029,8 @@ func (m *AppModel) loadMarketDataFromCoinGecko(symbols []string) error {
 			crypto := CryptoMarketInfo{
 				Symbol:           strings.ToUpper(symbol),
 				Name:             name,
-				Price:            price,
-				Change24h:        priceChange24h,
+				Price:            decimal.NewFromFloat(price),
+				Change24h:        decimal.NewFromFloat(priceChange24h),
 				ChangePercent24h: priceChangePercent24h,
 				Volume24h:        volume24h,
 				MarketCap:        marketCap,
@@ -801,8 +1108,8 @@ func (m *AppModel) loadBackupMarketData() error {
 		crypto := CryptoMarketInfo{
 			Symbol:           data.Symbol,
 			Name:             data.Name,
-			Price:            data.Price,
-			Change24h:        data.Price * data.Change24h / 100,
+			Price:            decimal.NewFromFloat(data.Price),
+			Change24h:        decimal.NewFromFloat(data.Price * data.Change24h / 100),
 			ChangePercent24h: data.Change24h,
 			Volume24h:        data.Volume24h,
 			MarketCap:        data.MarketCap,
@@ -901,8 +1208,7 @@ func (m *AppModel) LoadNewsData() error {
 func (m *AppModel) loadCoinGeckoNews() error {
 	url := "https://api.coingecko.com/api/v3/news?page=1"
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := m.httpGet(url, 10*time.Second)
 	if err != nil {
 		return err
 	}
@@ -990,8 +1296,7 @@ func (m *AppModel) loadNewsAPIHeadlines() error {
 	// Use the free everything endpoint without API key (limited but works)
 	url := "https://newsapi.org/v2/everything?q=bitcoin+OR+ethereum+OR+crypto+OR+cryptocurrency&sortBy=publishedAt&pageSize=10&language=en&apiKey=demo"
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := m.httpGet(url, 10*time.Second)
 	if err != nil {
 		return err
 	}
@@ -1369,8 +1674,7 @@ func (m *AppModel) loadCurrentCryptoNews() error {
 func (m *AppModel) loadCryptoPanicNews() error {
 	url := "https://cryptopanic.com/api/free/v1/posts/?auth_token=&filter=hot&public=true"
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := m.httpGet(url, 10*time.Second)
 	if err != nil {
 		return err
 	}
@@ -1447,8 +1751,7 @@ func (m *AppModel) loadCoinTelegraphNews() error {
 	// CoinTelegraph API alternative approach
 	url := "https://api.coindesk.com/v1/news.json"
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := m.httpGet(url, 10*time.Second)
 	if err != nil {
 		return err
 	}
@@ -1518,8 +1821,7 @@ func (m *AppModel) loadGenericCryptoNews() error {
 	// Try alternate endpoint with simple structure
 	url := "https://api.coindesk.com/v2/news/headlines.json"
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := m.httpGet(url, 10*time.Second)
 	if err != nil {
 		return err
 	}
@@ -1884,8 +2186,7 @@ func (m *AppModel) getLiveTokenPriceChange(symbol string) float64 {
 	// Call CoinGecko API for price change
 	url := fmt.Sprintf("https://api.coingecko.com/api/v3/simple/price?ids=%s&vs_currencies=usd&include_24hr_change=true", coinID)
 
-	client := &http.Client{Timeout: 3 * time.Second} // Short timeout to avoid delays
-	resp, err := client.Get(url)
+	resp, err := m.httpGet(url, 3*time.Second) // Short timeout to avoid delays
 	if err != nil {
 		// Return cached value if API fails
 		if cachedChange, exists := m.TokenPriceCache[symbol]; exists {
@@ -1954,76 +2255,326 @@ func (m *AppModel) loadExternalPrices(positions []CryptoPosition) {
 	for i := range positions {
 		pos := &positions[i]
 		if data, exists := priceData[pos.AssetCode]; exists {
-			pos.CurrentPrice = data.Current
+			pos.CurrentPrice = decimal.NewFromFloat(data.Current)
 			// Update market value based on current price
-			pos.MarketValue = pos.Quantity * data.Current
+			pos.MarketValue = pos.Quantity.Mul(pos.CurrentPrice)
 			// Use actual day change data
-			pos.DayChange = data.DayChange * pos.Quantity
+			pos.DayChange = decimal.NewFromFloat(data.DayChange).Mul(pos.Quantity)
 			pos.PercentChange = data.DayPercent
 		}
 	}
 }
 
 // GetLivePrice gets real-time price for a specific crypto symbol from Robinhood API or CoinGecko fallback
-func (m *AppModel) GetLivePrice(symbol string) (float64, error) {
+func (m *AppModel) GetLivePrice(symbol string) (decimal.Decimal, error) {
 	// Try Robinhood API first if authenticated
 	if m.CryptoClient != nil {
-		quotes, err := m.CryptoClient.GetBestBidAsk([]string{symbol})
+		// Keep the deadline short, this is called while the user is typing
+		ctx, cancel := context.WithTimeout(m.requestContext(), 5*time.Second)
+		defer cancel()
+
+		quotes, err := m.CryptoClient.GetBestBidAskContext(ctx, []string{symbol})
 		if err == nil && len(quotes) > 0 {
 			quote := quotes[0]
 			// Use the direct price if available
-			if quote.Price > 0 {
+			if quote.Price.IsPositive() {
 				return quote.Price, nil
 			}
 			// Use mid-price if bid/ask are available
-			if quote.BidPrice > 0 && quote.AskPrice > 0 {
-				return (quote.BidPrice + quote.AskPrice) / 2, nil
+			if quote.BidPrice.IsPositive() && quote.AskPrice.IsPositive() {
+				return quote.BidPrice.Add(quote.AskPrice).Div(decimal.NewFromInt(2)), nil
 			}
 		}
 	}
 
 	// Fallback to CoinGecko for trading prices when Robinhood fails or not authenticated
 	fallbackPrices := m.getLiveFallbackPrices([]string{symbol})
-	if price, exists := fallbackPrices[symbol]; exists && price > 0 {
+	if price, exists := fallbackPrices[symbol]; exists && price.IsPositive() {
 		return price, nil
 	}
 
-	return 0, fmt.Errorf("no price data available for %s", symbol)
+	return decimal.Zero, fmt.Errorf("no price data available for %s", symbol)
 }
 
-// updateEstimatedCost calculates the estimated cost for the current trading form
+// updateEstimatedCost calculates the estimated cost for the current trading form.
+// For USD amount orders the cost is the amount and the asset quantity is estimated instead.
 func (m *AppModel) updateEstimatedCost() {
+	m.TradingForm.EstimatedCost = decimal.Zero
+	m.TradingForm.EstimatedQuantity = decimal.Zero
+
 	if m.TradingForm.Quantity == "" {
-		m.TradingForm.EstimatedCost = 0
 		return
 	}
 
-	quantity, err := strconv.ParseFloat(m.TradingForm.Quantity, 64)
+	size, err := decimal.Parse(m.TradingForm.Quantity)
 	if err != nil {
-		m.TradingForm.EstimatedCost = 0
 		return
 	}
-
-	// Use current price for market orders, limit price for limit orders
-	price := m.TradingForm.CurrentPrice
+	if !m.TradingForm.QuoteAmount {
+		m.TradingForm.EstimatedQuantity = size
+	}
 
 	// For real-time estimation, we should have a cached price
-	if price == 0 {
-		m.TradingForm.EstimatedCost = 0
+	price := m.estimatedExecutionPrice()
+	if !price.IsPositive() {
 		return
 	}
 
-	if m.TradingForm.Type == "limit" && m.TradingForm.Price != "" {
-		if limitPrice, err := strconv.ParseFloat(m.TradingForm.Price, 64); err == nil {
+	if m.TradingForm.QuoteAmount {
+		m.TradingForm.EstimatedCost = size
+		m.TradingForm.EstimatedQuantity = size.Div(price)
+	} else {
+		m.TradingForm.EstimatedCost = size.Mul(price)
+	}
+}
+
+// estimatedExecutionPrice returns the price the order is expected to fill at:
+// the quoted execution price for market orders, the limit price for limit
+// orders and the stop price for stop-loss orders
+func (m *AppModel) estimatedExecutionPrice() decimal.Decimal {
+	price := m.TradingForm.CurrentPrice
+	if m.hasEstimatedPrice() {
+		price = m.TradingForm.QuotedPrice
+	}
+
+	if m.TradingForm.usesLimitPrice() && m.TradingForm.Price != "" {
+		if limitPrice, err := decimal.Parse(m.TradingForm.Price); err == nil {
 			price = limitPrice
 		}
+	} else if m.TradingForm.Type == "stop_loss" && m.TradingForm.StopPrice != "" {
+		if stopPrice, err := decimal.Parse(m.TradingForm.StopPrice); err == nil {
+			price = stopPrice
+		}
 	}
 
-	if price > 0 {
-		m.TradingForm.EstimatedCost = quantity * price
-	} else {
-		m.TradingForm.EstimatedCost = 0
+	return price
+}
+
+// validateStopPrice checks the stop price against the market: a sell stop
+// must sit below the current price and a buy stop above it
+func (m *AppModel) validateStopPrice() error {
+	stopPrice, err := decimal.Parse(m.TradingForm.StopPrice)
+	if err != nil || !stopPrice.IsPositive() {
+		return fmt.Errorf("stop price must be a positive number")
+	}
+
+	current := m.TradingForm.CurrentPrice
+	if !current.IsPositive() {
+		return nil
+	}
+	if m.TradingForm.Side == "sell" && !stopPrice.LessThan(current) {
+		return fmt.Errorf("sell stop price must be below the current price of %s", ui.FormatValue(current))
+	}
+	if m.TradingForm.Side == "buy" && !stopPrice.GreaterThan(current) {
+		return fmt.Errorf("buy stop price must be above the current price of %s", ui.FormatValue(current))
+	}
+	return nil
+}
+
+// validateLimitPrice checks the limit price, and for stop-limit orders that
+// it leaves room to fill once the stop triggers
+func (m *AppModel) validateLimitPrice() error {
+	limitPrice, err := decimal.Parse(m.TradingForm.Price)
+	if err != nil || !limitPrice.IsPositive() {
+		return fmt.Errorf("limit price must be a positive number")
+	}
+
+	if m.TradingForm.Type != "stop_limit" {
+		return nil
+	}
+	stopPrice, err := decimal.Parse(m.TradingForm.StopPrice)
+	if err != nil {
+		return nil
+	}
+	if m.TradingForm.Side == "sell" && limitPrice.GreaterThan(stopPrice) {
+		return fmt.Errorf("sell limit price must be at or below the stop price")
+	}
+	if m.TradingForm.Side == "buy" && limitPrice.LessThan(stopPrice) {
+		return fmt.Errorf("buy limit price must be at or above the stop price")
+	}
+	return nil
+}
+
+// LoadTradingPairs caches the order rules for every supported symbol
+func (m *AppModel) LoadTradingPairs() error {
+	if !m.Authenticated || m.CryptoClient == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	pairs, err := m.CryptoClient.GetTradingPairsContext(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to load trading pairs: %w", err)
+	}
+
+	registry := make(map[string]api.TradingPair, len(pairs))
+	for _, pair := range pairs {
+		registry[pair.Symbol] = pair
+	}
+	m.TradingPairs = registry
+	return nil
+}
+
+// tradingPair looks up the cached rules for symbol. Validation is skipped
+// when the registry could not be loaded; the API still enforces the rules.
+func (m *AppModel) tradingPair(symbol string) (api.TradingPair, bool) {
+	pair, ok := m.TradingPairs[symbol]
+	return pair, ok
+}
+
+// validateSymbol checks that the symbol is a tradable pair
+func (m *AppModel) validateSymbol() error {
+	if m.TradingPairs == nil {
+		return nil
+	}
+	pair, ok := m.tradingPair(m.TradingForm.Symbol)
+	if !ok {
+		return fmt.Errorf("%s is not a supported trading pair", m.TradingForm.Symbol)
+	}
+	if !pair.IsTradable() {
+		return fmt.Errorf("%s is not currently tradable", pair.Symbol)
+	}
+	return nil
+}
+
+// normalizeQuantity rounds the order size to the pair's increments, writing
+// the rounded value back to the form, and checks it against the size limits
+func (m *AppModel) normalizeQuantity() error {
+	size, err := decimal.Parse(m.TradingForm.Quantity)
+	if err != nil || !size.IsPositive() {
+		if m.TradingForm.QuoteAmount {
+			return fmt.Errorf("amount must be a positive number")
+		}
+		return fmt.Errorf("quantity must be a positive number")
+	}
+
+	pair, ok := m.tradingPair(m.TradingForm.Symbol)
+	if !ok {
+		return nil
+	}
+
+	if m.TradingForm.QuoteAmount {
+		amount := pair.RoundQuoteAmount(size)
+		if !amount.IsPositive() {
+			return fmt.Errorf("amount must be at least $%s", pair.QuoteIncrement)
+		}
+		m.TradingForm.Quantity = amount.String()
+		m.updateEstimatedCost()
+
+		// The exact quantity is set at execution, so only the limits can be checked
+		quantity := m.TradingForm.EstimatedQuantity
+		if quantity.IsPositive() && quantity.LessThan(pair.MinOrderSize) {
+			return fmt.Errorf("$%s is below the minimum order size of %s %s",
+				m.TradingForm.Quantity, pair.MinOrderSize, pair.AssetCode)
+		}
+		if quantity.IsPositive() && pair.MaxOrderSize.IsPositive() && quantity.GreaterThan(pair.MaxOrderSize) {
+			return fmt.Errorf("$%s is above the maximum order size of %s %s",
+				m.TradingForm.Quantity, pair.MaxOrderSize, pair.AssetCode)
+		}
+		return nil
+	}
+
+	quantity := pair.RoundQuantity(size)
+	m.TradingForm.Quantity = quantity.String()
+	m.updateEstimatedCost()
+	return pair.ValidateQuantity(quantity)
+}
+
+// normalizePrice rounds a limit or stop price field to the pair's quote increment
+func (m *AppModel) normalizePrice(field *string) error {
+	price, err := decimal.Parse(*field)
+	if err != nil || !price.IsPositive() {
+		return fmt.Errorf("price must be a positive number")
+	}
+
+	pair, ok := m.tradingPair(m.TradingForm.Symbol)
+	if !ok {
+		return nil
+	}
+
+	price = pair.RoundPrice(price)
+	*field = price.String()
+	m.updateEstimatedCost()
+	return pair.ValidatePrice(price)
+}
+
+// hasEstimatedPrice reports whether the execution quote matches the quantity being entered
+func (m *AppModel) hasEstimatedPrice() bool {
+	return m.TradingForm.QuotedPrice.IsPositive() && m.TradingForm.QuotedFor == m.TradingForm.quoteKey()
+}
+
+// quoteKey identifies the order an execution quote was requested for
+func (f TradingForm) quoteKey() string {
+	return f.Symbol + "/" + f.Side + "/" + f.sizeDisplay()
+}
+
+// assetCode returns the asset being traded, e.g. "BTC" for BTC-USD
+func (f TradingForm) assetCode() string {
+	return strings.TrimSuffix(f.Symbol, "-USD")
+}
+
+// sizeDisplay formats the order size as entered, e.g. "0.5" or "$250"
+func (f TradingForm) sizeDisplay() string {
+	if f.QuoteAmount {
+		return "$" + f.Quantity
 	}
+	return f.Quantity
+}
+
+// UpdateEstimatedPrice quotes the execution price for the trading form's
+// quantity and refreshes the estimated cost
+func (m *AppModel) UpdateEstimatedPrice() error {
+	if m.CryptoClient == nil || m.TradingForm.Symbol == "" {
+		return nil
+	}
+
+	quoteKey := m.TradingForm.quoteKey()
+	quantity, err := decimal.Parse(m.TradingForm.Quantity)
+	if err != nil || !quantity.IsPositive() {
+		return nil
+	}
+
+	// Quotes are per asset quantity, so convert a USD amount at the current price
+	if m.TradingForm.QuoteAmount {
+		if !m.TradingForm.CurrentPrice.IsPositive() {
+			return nil
+		}
+		quantity = quantity.Div(m.TradingForm.CurrentPrice).Round(8)
+	}
+
+	// Buys fill at the ask, sells at the bid
+	side := "ask"
+	if m.TradingForm.Side == "sell" {
+		side = "bid"
+	}
+
+	ctx, cancel := context.WithTimeout(m.requestContext(), 5*time.Second)
+	defer cancel()
+
+	quotes, err := m.CryptoClient.GetEstimatedPriceContext(ctx, m.TradingForm.Symbol, side, []decimal.Decimal{quantity})
+	if err != nil {
+		return err
+	}
+	if len(quotes) == 0 {
+		return fmt.Errorf("no estimated price returned for %s", m.TradingForm.Symbol)
+	}
+
+	quote := quotes[0]
+	executionPrice := quote.ExecutionPrice(m.TradingForm.Side)
+	if !executionPrice.IsPositive() {
+		return fmt.Errorf("no estimated price returned for %s", m.TradingForm.Symbol)
+	}
+
+	m.TradingForm.QuotedFor = quoteKey
+	m.TradingForm.QuotedPrice = executionPrice
+	m.TradingForm.SpreadCost = decimal.Zero
+	if quote.Price.IsPositive() {
+		m.TradingForm.SpreadCost = executionPrice.Sub(quote.Price).Abs().Mul(quantity)
+	}
+	m.updateEstimatedCost()
+	return nil
 }
 
 // HandleAPIKeySetup processes API key authentication
@@ -2032,46 +2583,57 @@ func (m *AppModel) HandleAPIKeySetup() error {
 		return nil // Already processing
 	}
 
+	if !m.verifyCredentials(m.APIKeyForm.APIKey) {
+		return nil
+	}
+
+	// API key is valid; ask for a passphrase to encrypt it before saving
+	credentials := m.APIKeyForm.APIKey
+	m.APIKeyForm = APIKeyForm{}
+	m.State = StateMenu
+	if !m.ephemeralCredentials && !m.credentials.ReadOnly() {
+		m.openPassphrase(passphraseNew, nil, credentials)
+	}
+
+	return nil
+}
+
+// verifyCredentials logs in with credentials once fetching the account with
+// them succeeds, reporting whether it did
+func (m *AppModel) verifyCredentials(credentials string) bool {
 	m.Loading = true
 	m.Error = ""
 
 	// Create crypto client with private key
-	m.CryptoClient = api.NewCryptoClient(m.APIKeyForm.APIKey)
+	m.CryptoClient = m.newCryptoClient(credentials)
 	if m.CryptoClient == nil {
 		m.Loading = false
 		m.Error = "Invalid format. Use: apikey:privatekey (privatekey in base64)"
-		return nil
+		return false
 	}
 
 	// Test the API key by fetching account info
-	_, err := m.CryptoClient.GetCryptoAccount()
+	ctx := m.requestContext()
+	_, err := m.CryptoClient.GetCryptoAccountContext(ctx)
 	if err != nil {
 		m.Loading = false
-		m.Error = fmt.Sprintf("Invalid API key: %v", err)
-		return nil
+		if ctx.Err() == nil {
+			m.Error = describeAPIError("Invalid API key", err)
+		}
+		return false
 	}
 
 	m.Loading = false
 
-	// API key is valid, save it
-	expiresAt := time.Now().Add(30 * 24 * time.Hour).Unix() // 30 days
-	if err := auth.SaveAPIKey(m.APIKeyForm.APIKey, "Crypto Trader", expiresAt); err != nil {
-		m.Error = fmt.Sprintf("Failed to save API key: %v", err)
-		return nil
-	}
-
 	// Set authenticated state
 	m.Authenticated = true
 	m.Username = "Crypto Trader"
 
-	// Load initial portfolio data
+	// Load initial portfolio data and the order rules for the trading screen
 	m.LoadCryptoPortfolio()
+	m.LoadTradingPairs()
 
-	// Clear API key form
-	m.APIKeyForm = APIKeyForm{}
-	m.State = StateMenu
-
-	return nil
+	return true
 }
 
 // PlaceCryptoOrder places a new crypto buy/sell order
@@ -2094,7 +2656,7 @@ func (m *AppModel) PlaceCryptoOrder(currencyID, side, orderType, quantity, price
 
 	_, err := m.CryptoClient.PlaceCryptoOrder(orderReq)
 	if err != nil {
-		return fmt.Errorf("failed to place order: %v", err)
+		return fmt.Errorf("failed to place order: %w", err)
 	}
 
 	// Refresh portfolio after placing order
@@ -2109,9 +2671,9 @@ func (m *AppModel) CancelCryptoOrder(orderID string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
-	err := m.CryptoClient.CancelCryptoOrder(orderID)
+	err := m.CryptoClient.CancelCryptoOrderContext(m.requestContext(), orderID)
 	if err != nil {
-		return fmt.Errorf("failed to cancel order: %v", err)
+		return fmt.Errorf("failed to cancel order: %w", err)
 	}
 
 	// Refresh portfolio after canceling order
@@ -2122,14 +2684,12 @@ func (m *AppModel) CancelCryptoOrder(orderID string) error {
 
 // HandleLogout logs out and clears stored API key
 func (m *AppModel) HandleLogout() {
-	m.Authenticated = false
-	m.Username = ""
-	m.CryptoClient = nil
-	m.Portfolio = nil
-	m.Error = ""
+	m.endSession()
 
-	// Clear stored API key
-	auth.ClearAPIKey()
+	// Clear stored API key, unless it is managed outside the app
+	if !m.ephemeralCredentials && !m.credentials.ReadOnly() {
+		m.credentials.Clear()
+	}
 
 	// Reset API key form
 	m.APIKeyForm = APIKeyForm{}
@@ -2137,43 +2697,135 @@ func (m *AppModel) HandleLogout() {
 	m.State = StateMenu
 }
 
-// PlaceOrder places a crypto order using the trading form data
-func (m *AppModel) PlaceOrder() error {
+// endSession logs out and drops everything loaded with the API key, leaving
+// the stored key in place
+func (m *AppModel) endSession() {
+	m.Authenticated = false
+	m.Username = ""
+	m.CryptoClient = nil
+	m.Portfolio = nil
+	m.OrderPages = nil
+	m.OlderOrders = nil
+	m.TradingPairs = nil
+	m.Accounts = nil
+	m.clearOrderFilter()
+	m.EditingFilter = false
+	m.OrderCursor = 0
+	m.DetailOrderID = ""
+	m.DetailOrder = nil
+	m.DetailQuote = nil
+	m.TrackedOrder = nil
+	m.Notice = ""
+	m.Error = ""
+	m.tickLoop++ // the session's refresh ticks stop with it
+}
+
+// orderSubmission is an order from the trading form that is ready to send,
+// with its intent already recorded
+type orderSubmission struct {
+	params api.OrderParams
+	intent auth.OrderIntent
+	retry  bool // the intent is from an earlier attempt whose outcome is unknown
+}
+
+// prepareOrder validates the trading form and records the order as a
+// pending intent. It runs in the key handler, before the order is sent, so
+// Submitting is set and a repeated Enter can't send the order again under a
+// new client_order_id.
+func (m *AppModel) prepareOrder() (orderSubmission, error) {
 	if !m.Authenticated || m.CryptoClient == nil {
-		return fmt.Errorf("not authenticated")
+		return orderSubmission{}, fmt.Errorf("not authenticated")
 	}
 
-	m.TradingForm.Submitting = true
+	// Prices only apply to the order types that use them
+	params := api.OrderParams{
+		ClientOrderID: uuid.New().String(),
+		Side:          m.TradingForm.Side,
+		Type:          m.TradingForm.Type,
+		Symbol:        m.TradingForm.Symbol,
+		TimeInForce:   m.TradingForm.TimeInForce,
+		AccountNumber: m.activeAccount(),
+	}
+	if m.TradingForm.QuoteAmount {
+		params.QuoteAmount = m.TradingForm.Quantity
+	} else {
+		params.Quantity = m.TradingForm.Quantity
+	}
+	if m.TradingForm.usesLimitPrice() {
+		params.LimitPrice = m.TradingForm.Price
+	}
+	if m.TradingForm.usesStopPrice() {
+		params.StopPrice = m.TradingForm.StopPrice
+	}
+	if err := params.Validate(); err != nil {
+		return orderSubmission{}, fmt.Errorf("failed to place order: %w", err)
+	}
 
-	// Create order request based on type
-	orderReq := api.OrderRequest{
-		Side:           m.TradingForm.Side,
-		Type:           m.TradingForm.Type,
-		TimeInForce:    "gtc", // Good Till Cancelled
-		Quantity:       m.TradingForm.Quantity,
-		CurrencyID:     "", // We'll use symbol instead
+	submission := orderSubmission{params: params, intent: m.newOrderIntent(params)}
+	if previous := m.TradingForm.Intent; previous != nil && previous.SameOrder(submission.intent) {
+		// Retrying the same order keeps its client_order_id
+		submission.intent = *previous
+		submission.params.ClientOrderID = previous.ClientOrderID
+		submission.retry = true
 	}
 
-	// For limit orders, add price
-	if m.TradingForm.Type == "limit" && m.TradingForm.Price != "" {
-		orderReq.Price = m.TradingForm.Price
+	if err := m.intents.Put(submission.intent); err != nil {
+		return orderSubmission{}, fmt.Errorf("order not sent: %w", err)
 	}
+	m.TradingForm.Intent = &submission.intent
+	m.submittingIntent = submission.intent.ClientOrderID
+	m.TradingForm.Submitting = true
+	return submission, nil
+}
 
-	// Use the new order placement method that matches the API
-	clientOrderID := uuid.New().String()
-	_, err := m.CryptoClient.PlaceCryptoOrderNew(
-		clientOrderID,
-		m.TradingForm.Side,
-		m.TradingForm.Type,
-		m.TradingForm.Symbol,
-		m.TradingForm.Quantity,
-		m.TradingForm.Price,
-	)
+// PlaceOrder sends an order prepared by prepareOrder. If Robinhood's answer
+// is lost the intent keeps its client_order_id, so retrying looks the order
+// up instead of placing it twice.
+func (m *AppModel) PlaceOrder(submission orderSubmission) (*api.CryptoOrder, error) {
+	defer func() {
+		m.TradingForm.Submitting = false
+		m.submittingIntent = ""
+	}()
+
+	// Not tied to the screen context: aborting a submission halfway would
+	// leave the order's outcome unknown.
+	ctx := context.Background()
+	intent := submission.intent
 
-	m.TradingForm.Submitting = false
+	if submission.retry {
+		// If the earlier attempt went through, report that order rather
+		// than sending another
+		order, err := m.findIntentOrder(ctx, intent)
+		if err != nil {
+			return nil, &outcomeUnknownError{err: fmt.Errorf("could not check for the earlier attempt: %w", err)}
+		}
+		if order != nil {
+			m.intents.Remove(intent.ClientOrderID)
+			return m.orderPlaced(order), nil
+		}
+	}
 
+	order, err := m.CryptoClient.PlaceCryptoOrderWithParamsContext(ctx, submission.params)
 	if err != nil {
-		return fmt.Errorf("failed to place order: %v", err)
+		if orderOutcomeUnknown(err) {
+			return nil, &outcomeUnknownError{err: err}
+		}
+		// Robinhood answered, so the order was definitely not placed
+		m.intents.Remove(intent.ClientOrderID)
+		m.TradingForm.Intent = nil
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	// If this fails the intent is settled by the next refresh instead
+	m.intents.Remove(intent.ClientOrderID)
+	return m.orderPlaced(order), nil
+}
+
+// orderPlaced finishes a successful submission: the trading form is reset
+// and the portfolio refreshed to show the new order
+func (m *AppModel) orderPlaced(order *api.CryptoOrder) *api.CryptoOrder {
+	if order.Quantity.IsZero() {
+		order.Quantity = m.TradingForm.EstimatedQuantity
 	}
 
 	// Reset trading form and go back to menu
@@ -2184,7 +2836,58 @@ func (m *AppModel) PlaceOrder() error {
 	// Refresh portfolio to show new order
 	go m.LoadCryptoPortfolio()
 
-	return nil
+	return order
+}
+
+// describeAPIError turns an API failure into a message that tells the user
+// what went wrong and what to do about it
+func describeAPIError(action string, err error) string {
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) {
+		return fmt.Sprintf("%s: %v", action, err)
+	}
+
+	switch {
+	case api.IsAuthError(err):
+		return fmt.Sprintf("%s: Robinhood rejected the API key (HTTP %d). Please re-enter your API key.", action, apiErr.StatusCode)
+	case api.IsRateLimited(err):
+		return fmt.Sprintf("%s: rate limited by Robinhood, will retry on the next refresh", action)
+	case api.IsServerError(err):
+		return fmt.Sprintf("%s: Robinhood is having problems (HTTP %d), will retry on the next refresh", action, apiErr.StatusCode)
+	case apiErr.Detail() != "":
+		return fmt.Sprintf("%s: %s", action, apiErr.Detail())
+	}
+	return fmt.Sprintf("%s: %v", action, err)
+}
+
+// promptForAPIKey drops the rejected credentials and sends the user to the
+// API key setup screen with an explanation
+func (m *AppModel) promptForAPIKey(err error) {
+	m.CancelRequests()
+	m.Authenticated = false
+	m.CryptoClient = nil
+	m.TrackedOrder = nil
+	m.APIKeyForm = APIKeyForm{}
+	m.State = StateLogin
+	m.Error = describeAPIError("Session ended", err)
+}
+
+// requestContext returns the context for API calls made on behalf of the
+// current screen, creating it on first use
+func (m *AppModel) requestContext() context.Context {
+	if m.requestCtx == nil {
+		m.requestCtx, m.cancelRequest = context.WithCancel(context.Background())
+	}
+	return m.requestCtx
+}
+
+// CancelRequests aborts every in-flight API call started from the current
+// screen. Calls made afterwards get a fresh context.
+func (m *AppModel) CancelRequests() {
+	if m.cancelRequest != nil {
+		m.cancelRequest()
+	}
+	m.requestCtx, m.cancelRequest = context.WithCancel(context.Background())
 }
 
 // Bubble Tea interface methods
@@ -2193,10 +2896,12 @@ func (m *AppModel) Init() tea.Cmd {
 	if m.Authenticated {
 		return tea.Batch(
 			m.loadCryptoPortfolioCmd(),
-			tickEvery(5*time.Second),
+			m.loadTradingPairsCmd(),
+			m.startTicking(),
 		)
 	}
-	return nil
+	// Otherwise look for a stored API key to unlock
+	return m.loadStoredKeyCmd(false)
 }
 
 func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -2207,32 +2912,51 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tickMsg:
+		// The loop was replaced or its session ended, let this tick go
+		if msg.loop != m.tickLoop {
+			return m, nil
+		}
+
+		// Skip this round rather than spend the requests that user actions need
+		if !m.hasRefreshBudget() {
+			return m, m.tickEvery(5 * time.Second)
+		}
+
 		// Auto-refresh data based on current state
 		if (m.State == StateDashboard || m.State == StatePortfolio || m.State == StateOrderHistory) && m.Authenticated && !m.Loading {
 			return m, tea.Batch(
 				m.loadCryptoPortfolioCmd(),
-				tickEvery(5*time.Second),
+				m.tickEvery(5*time.Second),
 			)
 		} else if m.State == StateMarketData && !m.Loading {
 			return m, tea.Batch(
 				m.loadMarketDataCmd(),
-				tickEvery(30*time.Second), // Market data refreshes every 30 seconds
+				m.tickEvery(30*time.Second), // Market data refreshes every 30 seconds
 			)
 		} else if m.State == StateNews && !m.Loading {
 			return m, tea.Batch(
 				m.loadNewsDataCmd(),
-				tickEvery(15*60*time.Second), // News refreshes every 15 minutes
+				m.tickEvery(15*60*time.Second), // News refreshes every 15 minutes
+			)
+		} else if m.State == StateOrderDetail && m.DetailOrder != nil && !m.DetailOrder.IsTerminal() && !m.LoadingDetail {
+			return m, tea.Batch(
+				m.loadOrderDetailCmd(),
+				m.tickEvery(5*time.Second), // Follow fills while the order is still open
 			)
 		} else if m.State == StateTrading && m.TradingForm.Symbol != "" && !m.Loading {
 			return m, tea.Batch(
 				m.updateTradingPriceCmd(),
-				tickEvery(10*time.Second), // Trading prices refresh every 10 seconds (reduced to avoid rate limits)
+				m.tickEvery(10*time.Second), // Trading prices refresh every 10 seconds (reduced to avoid rate limits)
 			)
 		}
-		return m, tickEvery(5*time.Second)
+		return m, m.tickEvery(5*time.Second)
 
 	case cryptoPortfolioLoadedMsg:
 		// Crypto portfolio data loaded, clear any loading state
+		if api.IsAuthError(msg.err) {
+			m.promptForAPIKey(msg.err)
+			return m, nil
+		}
 		if msg.err != nil && m.Error == "" {
 			m.Error = fmt.Sprintf("Failed to load crypto portfolio: %v", msg.err)
 		}
@@ -2257,19 +2981,79 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err == nil && m.Authenticated {
 			return m, tea.Batch(
 				m.loadCryptoPortfolioCmd(),
-				tickEvery(5*time.Second),
+				m.startTicking(),
 			)
 		}
 		return m, nil
 
 	case orderPlacedMsg:
 		// Order placement completed
-		if msg.err != nil {
-			m.Error = fmt.Sprintf("Order failed: %v", msg.err)
+		var unknown *outcomeUnknownError
+		if api.IsAuthError(msg.err) {
+			m.promptForAPIKey(msg.err)
+		} else if errors.As(msg.err, &unknown) {
+			m.Error = fmt.Sprintf("Order outcome unknown (%v). Press ENTER to retry safely: the order keeps its ID and can't be placed twice", unknown.err)
+		} else if msg.err != nil {
+			m.Error = describeAPIError("Order failed", msg.err)
 		} else {
 			m.Error = ""
-			// Order was successful, refresh portfolio
-			return m, m.loadCryptoPortfolioCmd()
+			m.Notice = ""
+			// Order was successful, refresh portfolio and follow it until it settles
+			return m, tea.Batch(m.loadCryptoPortfolioCmd(), m.trackOrder(msg.order))
+		}
+		return m, nil
+
+	case orderProgressMsg:
+		return m, m.handleOrderProgress(msg)
+
+	case orderCompletedMsg:
+		return m, m.handleOrderCompleted(msg)
+
+	case tradingPairsLoadedMsg:
+		// Without the registry the trading screen falls back to API-side validation
+		if api.IsAuthError(msg.err) {
+			m.promptForAPIKey(msg.err)
+		}
+		return m, nil
+
+	case olderOrdersLoadedMsg:
+		// Older order page loaded, errors are already reported by LoadOlderOrders
+		if api.IsAuthError(msg.err) {
+			m.promptForAPIKey(msg.err)
+		}
+		return m, nil
+
+	case accountsLoadedMsg:
+		// Errors are already reported by LoadAccounts
+		if api.IsAuthError(msg.err) {
+			m.promptForAPIKey(msg.err)
+		}
+		return m, nil
+
+	case bulkCancelOrdersLoadedMsg:
+		// Errors are already reported by LoadBulkCancelOrders
+		if api.IsAuthError(msg.err) {
+			m.promptForAPIKey(msg.err)
+		}
+		return m, nil
+
+	case bulkCancelDoneMsg:
+		return m, m.handleBulkCancelDone()
+
+	case storedKeyLoadedMsg:
+		return m, m.handleStoredKeyLoaded(msg)
+
+	case passphraseDoneMsg:
+		// Errors are already reported by SubmitPassphrase
+		if msg.unlocked {
+			return m, m.startSession()
+		}
+		return m, nil
+
+	case orderDetailLoadedMsg:
+		// Errors are already reported by LoadOrderDetail
+		if api.IsAuthError(msg.err) {
+			m.promptForAPIKey(msg.err)
 		}
 		return m, nil
 
@@ -2281,13 +3065,24 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		return m.handleKeyPress(msg)
+		prevState := m.State
+		model, cmd := m.handleKeyPress(msg)
+		// Leaving a screen (or pressing Esc) aborts the requests it started
+		if m.State != prevState || msg.String() == "esc" {
+			m.CancelRequests()
+		}
+		return model, cmd
 	}
 
 	return m, nil
 }
 
 func (m *AppModel) View() string {
+	return m.profileStatus() + m.screenView()
+}
+
+// screenView renders the screen for the current state
+func (m *AppModel) screenView() string {
 	switch m.State {
 	case StateMenu:
 		return m.menuView()
@@ -2303,6 +3098,18 @@ func (m *AppModel) View() string {
 		return m.marketDataView()
 	case StateOrderHistory:
 		return m.orderHistoryView()
+	case StateOrderDetail:
+		return m.orderDetailView()
+	case StateAccounts:
+		return m.accountsView()
+	case StateBulkCancel:
+		return m.bulkCancelView()
+	case StatePassphrase:
+		return m.passphraseView()
+	case StateProfiles:
+		return m.profilesView()
+	case StateKeyGen:
+		return m.keyGenView()
 	case StateNews:
 		return m.newsView()
 	case StateHelp:
@@ -2313,17 +3120,43 @@ func (m *AppModel) View() string {
 }
 
 // Message types for Bubble Tea
-type tickMsg time.Time
+type tickMsg struct{ loop int }
+
+// refreshBudgetReserve is the number of API requests kept back for
+// user-initiated actions; auto-refresh pauses below it
+const refreshBudgetReserve = 20
+
+// hasRefreshBudget reports whether the rate limiter can afford an auto-refresh
+func (m *AppModel) hasRefreshBudget() bool {
+	if m.CryptoClient == nil {
+		return true
+	}
+	remaining := m.CryptoClient.RateLimitRemaining()
+	return remaining < 0 || remaining >= refreshBudgetReserve
+}
 type cryptoPortfolioLoadedMsg struct{ err error }
 type marketDataLoadedMsg struct{ err error }
 type newsDataLoadedMsg struct{ err error }
 type apiKeySetupCompletedMsg struct{ err error }
-type orderPlacedMsg struct{ err error }
+type orderPlacedMsg struct {
+	order *api.CryptoOrder
+	err   error
+}
 type tradingPriceUpdatedMsg struct{ err error }
+type olderOrdersLoadedMsg struct{ err error }
+type tradingPairsLoadedMsg struct{ err error }
+
+// startTicking starts a new refresh tick loop, ending the one running
+func (m *AppModel) startTicking() tea.Cmd {
+	m.tickLoop++
+	return m.tickEvery(5 * time.Second)
+}
 
-func tickEvery(d time.Duration) tea.Cmd {
-	return tea.Tick(d, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+// tickEvery schedules the next tick of the live loop
+func (m *AppModel) tickEvery(d time.Duration) tea.Cmd {
+	loop := m.tickLoop
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return tickMsg{loop: loop}
 	})
 }
 
@@ -2334,6 +3167,20 @@ func (m *AppModel) loadCryptoPortfolioCmd() tea.Cmd {
 	}
 }
 
+func (m *AppModel) loadOlderOrdersCmd() tea.Cmd {
+	return func() tea.Msg {
+		err := m.LoadOlderOrders()
+		return olderOrdersLoadedMsg{err: err}
+	}
+}
+
+func (m *AppModel) loadTradingPairsCmd() tea.Cmd {
+	return func() tea.Msg {
+		err := m.LoadTradingPairs()
+		return tradingPairsLoadedMsg{err: err}
+	}
+}
+
 func (m *AppModel) loadMarketDataCmd() tea.Cmd {
 	return func() tea.Msg {
 		err := m.LoadMarketData()
@@ -2355,10 +3202,17 @@ func (m *AppModel) apiKeySetupCmd() tea.Cmd {
 	}
 }
 
+// placeOrderCmd prepares the order right away and sends it in the background
 func (m *AppModel) placeOrderCmd() tea.Cmd {
+	submission, err := m.prepareOrder()
+	if err != nil {
+		return func() tea.Msg {
+			return orderPlacedMsg{err: err}
+		}
+	}
 	return func() tea.Msg {
-		err := m.PlaceOrder()
-		return orderPlacedMsg{err: err}
+		order, err := m.PlaceOrder(submission)
+		return orderPlacedMsg{order: order, err: err}
 	}
 }
 
@@ -2370,21 +3224,31 @@ func (m *AppModel) updateTradingPriceCmd() tea.Cmd {
 				// Update estimated cost when price changes
 				m.updateEstimatedCost()
 			}
+			// Re-quote the execution price, keeping the last quote if that fails
+			m.UpdateEstimatedPrice()
 		}
 		return tradingPriceUpdatedMsg{err: nil}
 	}
 }
 
+func (m *AppModel) estimatedPriceCmd() tea.Cmd {
+	return func() tea.Msg {
+		// Without a quote the estimate falls back to the current price
+		m.UpdateEstimatedPrice()
+		return tradingPriceUpdatedMsg{err: nil}
+	}
+}
+
 func (m *AppModel) fetchTradingPriceCmd() tea.Cmd {
 	return func() tea.Msg {
 		if m.TradingForm.Symbol != "" {
 			// Try Robinhood API first
-			if price, err := m.GetLivePrice(m.TradingForm.Symbol); err == nil && price > 0 {
+			if price, err := m.GetLivePrice(m.TradingForm.Symbol); err == nil && price.IsPositive() {
 				m.TradingForm.CurrentPrice = price
 			} else {
 				// Fallback to CoinGecko
 				fallbackPrices := m.getLiveFallbackPrices([]string{m.TradingForm.Symbol})
-				if fallbackPrice, exists := fallbackPrices[m.TradingForm.Symbol]; exists && fallbackPrice > 0 {
+				if fallbackPrice, exists := fallbackPrices[m.TradingForm.Symbol]; exists && fallbackPrice.IsPositive() {
 					m.TradingForm.CurrentPrice = fallbackPrice
 				}
 			}
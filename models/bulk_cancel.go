@@ -0,0 +1,320 @@
+package models
+
+import (
+	"context"
+	"dazedtrader/api"
+	"dazedtrader/ui"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Steps of the bulk cancel screen
+const (
+	bulkCancelStepFilter  = iota // choosing which open orders to cancel
+	bulkCancelStepReview         // listing the matching orders for confirmation
+	bulkCancelStepRunning        // cancel requests in flight
+	bulkCancelStepDone           // per-order results
+)
+
+// Rows of the bulk cancel filter, in display order
+const (
+	bulkCancelRowSymbol = iota
+	bulkCancelRowSide
+	bulkCancelRowCount
+)
+
+// BulkCancel holds the state of the bulk cancel screen. Side is an index
+// into filterSides.
+type BulkCancel struct {
+	Step    int
+	Cursor  int
+	Symbol  string
+	Side    int
+	Orders  []api.CryptoOrder
+	Results []api.CancelResult
+	Loading bool
+}
+
+// bulkCancelOrdersLoadedMsg is sent once the open orders to cancel have
+// been listed
+type bulkCancelOrdersLoadedMsg struct{ err error }
+
+// bulkCancelDoneMsg is sent once every cancel request has finished
+type bulkCancelDoneMsg struct{}
+
+// filter returns the API filter for the chosen symbol and side
+func (b BulkCancel) filter() api.OrderFilter {
+	return api.OrderFilter{Symbol: b.Symbol, Side: filterSides[b.Side]}
+}
+
+// scope describes which open orders are canceled, e.g. "open BTC-USD sell orders"
+func (b BulkCancel) scope() string {
+	parts := []string{"open"}
+	if b.Symbol != "" {
+		parts = append(parts, b.Symbol)
+	}
+	if side := filterSides[b.Side]; side != "" {
+		parts = append(parts, side)
+	}
+	return strings.Join(parts, " ") + " orders"
+}
+
+// openBulkCancel shows the bulk cancel screen, starting from the order
+// history filter's symbol and side
+func (m *AppModel) openBulkCancel() {
+	if !m.Authenticated || m.CryptoClient == nil {
+		return
+	}
+
+	m.BulkCancel = BulkCancel{}
+	if m.orderFilterActive() {
+		m.BulkCancel.Symbol = m.FilterForm.Symbol
+		m.BulkCancel.Side = m.FilterForm.Side
+	}
+	m.Error = ""
+	m.State = StateBulkCancel
+}
+
+// LoadBulkCancelOrders lists the open orders matching the bulk cancel filter
+func (m *AppModel) LoadBulkCancelOrders() error {
+	if !m.Authenticated || m.CryptoClient == nil {
+		return nil
+	}
+
+	m.BulkCancel.Loading = true
+	defer func() {
+		m.BulkCancel.Loading = false
+	}()
+
+	ctx := m.requestContext()
+	orders, err := m.CryptoClient.GetOpenCryptoOrdersContext(ctx, m.BulkCancel.filter())
+	if err != nil && ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		m.Error = describeAPIError("Failed to load open orders", err)
+		return err
+	}
+
+	m.BulkCancel.Orders = orders
+	return nil
+}
+
+func (m *AppModel) loadBulkCancelOrdersCmd() tea.Cmd {
+	return func() tea.Msg {
+		err := m.LoadBulkCancelOrders()
+		return bulkCancelOrdersLoadedMsg{err: err}
+	}
+}
+
+// RunBulkCancel cancels every listed order. Like order placement it isn't
+// tied to the screen: once started, leaving can't leave it half done.
+func (m *AppModel) RunBulkCancel() {
+	ids := make([]string, len(m.BulkCancel.Orders))
+	for i, order := range m.BulkCancel.Orders {
+		ids[i] = order.ID
+	}
+	m.BulkCancel.Results = m.CryptoClient.CancelCryptoOrdersContext(context.Background(), ids)
+}
+
+func (m *AppModel) runBulkCancelCmd() tea.Cmd {
+	return func() tea.Msg {
+		m.RunBulkCancel()
+		return bulkCancelDoneMsg{}
+	}
+}
+
+// handleBulkCancelDone reports the results and refreshes the order history
+func (m *AppModel) handleBulkCancelDone() tea.Cmd {
+	m.BulkCancel.Step = bulkCancelStepDone
+	for _, result := range m.BulkCancel.Results {
+		if api.IsAuthError(result.Err) {
+			m.promptForAPIKey(result.Err)
+			return nil
+		}
+	}
+
+	if m.orderFilterActive() {
+		return tea.Batch(m.loadCryptoPortfolioCmd(), m.loadFilteredOrdersCmd())
+	}
+	return m.loadCryptoPortfolioCmd()
+}
+
+// handleBulkCancelKeys takes all keys on the bulk cancel screen so symbols
+// can be typed freely
+func (m *AppModel) handleBulkCancelKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b := &m.BulkCancel
+
+	switch b.Step {
+	case bulkCancelStepFilter:
+		switch msg.String() {
+		case "esc":
+			m.State = StateOrderHistory
+			m.Error = ""
+		case "enter":
+			b.Step = bulkCancelStepReview
+			b.Orders = nil
+			m.Error = ""
+			return m, m.loadBulkCancelOrdersCmd()
+		case "up":
+			b.Cursor = (b.Cursor + bulkCancelRowCount - 1) % bulkCancelRowCount
+		case "down", "tab":
+			b.Cursor = (b.Cursor + 1) % bulkCancelRowCount
+		case "left":
+			if b.Cursor == bulkCancelRowSide {
+				b.Side = (b.Side + len(filterSides) - 1) % len(filterSides)
+			}
+		case "right", " ":
+			if b.Cursor == bulkCancelRowSide {
+				b.Side = (b.Side + 1) % len(filterSides)
+			}
+		case "backspace":
+			if b.Cursor == bulkCancelRowSymbol && len(b.Symbol) > 0 {
+				b.Symbol = b.Symbol[:len(b.Symbol)-1]
+			}
+		default:
+			if b.Cursor == bulkCancelRowSymbol && len(msg.String()) == 1 {
+				char := msg.String()[0]
+				// Allow letters, numbers, and hyphens for crypto symbols
+				if (char >= 'A' && char <= 'Z') || (char >= 'a' && char <= 'z') ||
+					(char >= '0' && char <= '9') || char == '-' {
+					b.Symbol += strings.ToUpper(string(char))
+				}
+			}
+		}
+
+	case bulkCancelStepReview:
+		switch msg.String() {
+		case "esc", "n":
+			b.Step = bulkCancelStepFilter
+			m.Error = ""
+		case "y":
+			if b.Loading || len(b.Orders) == 0 {
+				return m, nil
+			}
+			b.Step = bulkCancelStepRunning
+			m.Error = ""
+			return m, m.runBulkCancelCmd()
+		case "r", "f5":
+			if !b.Loading {
+				m.Error = ""
+				return m, m.loadBulkCancelOrdersCmd()
+			}
+		}
+
+	case bulkCancelStepDone:
+		switch msg.String() {
+		case "esc", "enter":
+			m.State = StateOrderHistory
+		}
+	}
+
+	// Nothing can be interrupted while the cancel requests are in flight
+	return m, nil
+}
+
+// bulkCancelOrderLine describes an order on the bulk cancel screen
+func bulkCancelOrderLine(order api.CryptoOrder) string {
+	created := order.CreatedAt
+	if len(created) > 16 {
+		created = strings.Replace(created[:16], "T", " ", 1)
+	}
+
+	size := order.Quantity.String()
+	if order.QuoteAmount.IsPositive() {
+		size = ui.FormatValue(order.QuoteAmount) + " of"
+	}
+	return fmt.Sprintf("%-4s %s %s %s • %s • created %s",
+		strings.ToUpper(order.Side),
+		size,
+		order.Symbol,
+		orderTypeName(order.Type),
+		strings.ReplaceAll(order.State, "_", " "),
+		created,
+	)
+}
+
+// bulkCancelView renders the bulk cancel screen for its current step
+func (m *AppModel) bulkCancelView() string {
+	title := ui.HeaderStyle.Render("🚫 CANCEL OPEN ORDERS")
+	b := m.BulkCancel
+
+	var content strings.Builder
+
+	if m.Error != "" {
+		content.WriteString(ui.NegativeStyle.Render("❌ " + m.Error + "\n\n"))
+	}
+	content.WriteString(m.accountStatus())
+
+	var footer string
+	switch b.Step {
+	case bulkCancelStepFilter:
+		content.WriteString("Cancel every open order, or only those for one symbol or side.\n\n")
+
+		symbol := b.Symbol
+		if symbol == "" {
+			symbol = "Any"
+		}
+		side := filterSides[b.Side]
+		if side == "" {
+			side = "Any"
+		}
+		rows := []string{
+			fmt.Sprintf("Symbol: %s", symbol),
+			fmt.Sprintf("Side:   ◀ %s ▶", strings.ToUpper(side[:1])+side[1:]),
+		}
+		for i, row := range rows {
+			if i == b.Cursor {
+				content.WriteString(ui.SelectedStyle.Render("► "+row) + "\n")
+			} else {
+				content.WriteString(ui.UnselectedStyle.Render("  "+row) + "\n")
+			}
+		}
+		footer = ui.InfoStyle.Render("↑↓ select • type a symbol • ←→ change side • Enter to list orders • 'Esc' to go back")
+
+	case bulkCancelStepReview:
+		footer = ui.InfoStyle.Render("'Y' to cancel them • 'N' or 'Esc' to change the selection • 'R' or 'F5' to refresh")
+		if b.Orders == nil {
+			if b.Loading {
+				content.WriteString(ui.LoadingStyle.Render(fmt.Sprintf("🔄 Loading %s...\n", b.scope())))
+			} else {
+				content.WriteString("📊 Orders not loaded.\nPress 'R' or 'F5' to retry.\n")
+			}
+			break
+		}
+		if len(b.Orders) == 0 {
+			content.WriteString(fmt.Sprintf("📊 No %s to cancel.\n", b.scope()))
+			footer = ui.InfoStyle.Render("'N' or 'Esc' to change the selection • 'R' or 'F5' to refresh")
+			break
+		}
+
+		content.WriteString(fmt.Sprintf("These %d %s will be canceled:\n\n", len(b.Orders), b.scope()))
+		for _, order := range b.Orders {
+			content.WriteString("  " + bulkCancelOrderLine(order) + "\n")
+		}
+		content.WriteString("\n" + ui.NegativeStyle.Render(fmt.Sprintf("⚠ Cancel %d order(s)? Filled quantities stay filled.", len(b.Orders))) + "\n")
+
+	case bulkCancelStepRunning:
+		content.WriteString(ui.LoadingStyle.Render(fmt.Sprintf("🔄 Canceling %d order(s)...\n", len(b.Orders))))
+		footer = ui.InfoStyle.Render("Please wait until every cancel request has finished")
+
+	case bulkCancelStepDone:
+		canceled := 0
+		for i, result := range b.Results {
+			line := bulkCancelOrderLine(b.Orders[i])
+			if result.Err == nil {
+				canceled++
+				content.WriteString(ui.PositiveStyle.Render("✅ "+line) + "\n")
+			} else {
+				content.WriteString(ui.NegativeStyle.Render("❌ "+line) + "\n")
+				content.WriteString(ui.NegativeStyle.Render("   "+describeAPIError("Not canceled", result.Err)) + "\n")
+			}
+		}
+		content.WriteString(fmt.Sprintf("\n%d of %d order(s) canceled\n", canceled, len(b.Results)))
+		footer = ui.InfoStyle.Render("Enter or 'Esc' to return to order history")
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
+}
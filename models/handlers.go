@@ -1,9 +1,9 @@
 package models
 
 import (
+	"dazedtrader/decimal"
 	"dazedtrader/ui"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/atotto/clipboard"
@@ -11,9 +11,28 @@ import (
 )
 
 func (m *AppModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The order filter form takes all keys so symbols can be typed freely
+	if m.State == StateOrderHistory && m.EditingFilter && msg.String() != "ctrl+c" {
+		return m.handleOrderFilterKeys(msg)
+	}
+	// So do the bulk cancel and passphrase screens
+	if m.State == StateBulkCancel && msg.String() != "ctrl+c" {
+		return m.handleBulkCancelKeys(msg)
+	}
+	if m.State == StatePassphrase && msg.String() != "ctrl+c" {
+		return m.handlePassphraseKeys(msg)
+	}
+	if m.State == StateProfiles && msg.String() != "ctrl+c" {
+		return m.handleProfileKeys(msg)
+	}
+	if m.State == StateKeyGen && msg.String() != "ctrl+c" {
+		return m.handleKeyGenKeys(msg)
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "q":
 		if m.State == StateMenu {
+			m.CancelRequests()
 			return m, tea.Quit
 		}
 		// Only quit from menu, otherwise go back
@@ -22,6 +41,18 @@ func (m *AppModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "esc":
+		// The account picker goes back to the screen it was opened from
+		if m.State == StateAccounts {
+			m.State = m.AccountReturnState
+			m.Error = ""
+			return m, nil
+		}
+		// The order detail screen goes back to the order history
+		if m.State == StateOrderDetail {
+			m.State = StateOrderHistory
+			m.Error = ""
+			return m, nil
+		}
 		// Always go back or to menu
 		// Reset trading form when leaving trading state
 		if m.State == StateTrading {
@@ -36,6 +67,9 @@ func (m *AppModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Refresh data based on current state
 		if (m.State == StateDashboard || m.State == StatePortfolio || m.State == StateOrderHistory) && m.Authenticated && !m.Loading {
 			m.Error = ""
+			if m.State == StateOrderHistory && m.orderFilterActive() {
+				return m, tea.Batch(m.loadCryptoPortfolioCmd(), m.loadFilteredOrdersCmd())
+			}
 			return m, m.loadCryptoPortfolioCmd()
 		} else if m.State == StateMarketData && !m.Loading {
 			m.Error = ""
@@ -43,6 +77,9 @@ func (m *AppModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		} else if m.State == StateNews && !m.Loading {
 			m.Error = ""
 			return m, m.loadNewsDataCmd()
+		} else if m.State == StateOrderDetail && m.Authenticated && !m.LoadingDetail {
+			m.Error = ""
+			return m, m.loadOrderDetailCmd()
 		}
 		return m, nil
 
@@ -50,6 +87,9 @@ func (m *AppModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Only handle 'r' for refresh if NOT in API key setup
 		if m.State != StateLogin && (m.State == StateDashboard || m.State == StatePortfolio || m.State == StateOrderHistory) && m.Authenticated && !m.Loading {
 			m.Error = ""
+			if m.State == StateOrderHistory && m.orderFilterActive() {
+				return m, tea.Batch(m.loadCryptoPortfolioCmd(), m.loadFilteredOrdersCmd())
+			}
 			return m, m.loadCryptoPortfolioCmd()
 		} else if m.State == StateMarketData && !m.Loading {
 			m.Error = ""
@@ -57,6 +97,9 @@ func (m *AppModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		} else if m.State == StateNews && !m.Loading {
 			m.Error = ""
 			return m, m.loadNewsDataCmd()
+		} else if m.State == StateOrderDetail && m.Authenticated && !m.LoadingDetail {
+			m.Error = ""
+			return m, m.loadOrderDetailCmd()
 		}
 		// If in login state, don't handle it globally - let it fall through to login handler
 		if m.State == StateLogin {
@@ -83,6 +126,8 @@ func (m *AppModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleOrderHistoryKeys(msg)
 	case StateNews:
 		return m.handleNewsKeys(msg)
+	case StateAccounts:
+		return m.handleAccountKeys(msg)
 	}
 
 	return m, nil
@@ -100,6 +145,9 @@ func (m *AppModel) handleMenuKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "enter", " ":
 		return m.handleMenuSelection()
+	case "p":
+		// Pick the profile whose API key is used
+		m.openProfilePicker()
 	case "1":
 		if m.Authenticated {
 			m.Cursor = 0
@@ -163,8 +211,12 @@ func (m *AppModel) handleMenuSelection() (tea.Model, tea.Cmd) {
 		}
 	case 5: // API Key Setup
 		if !m.Authenticated {
-			m.State = StateLogin
 			m.Error = ""
+			if m.LoadingKey {
+				return m, nil
+			}
+			// Unlock a stored key rather than ask for it again
+			return m, m.loadStoredKeyCmd(true)
 		}
 	case 6: // Help
 		m.State = StateHelp
@@ -173,6 +225,7 @@ func (m *AppModel) handleMenuSelection() (tea.Model, tea.Cmd) {
 			m.HandleLogout()
 		}
 	case 8: // Exit
+		m.CancelRequests()
 		return m, tea.Quit
 	}
 	return m, nil
@@ -215,6 +268,11 @@ func (m *AppModel) handleLoginKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.APIKeyForm.APIKey = ""
 		return m, nil
 
+	case "ctrl+g":
+		// Generate a key pair to create the API key with
+		m.openKeyGen()
+		return m, nil
+
 	default:
 		// Handle text input for API key
 		if len(msg.String()) == 1 {
@@ -228,12 +286,20 @@ func (m *AppModel) handleLoginKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *AppModel) handleDashboardKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Dashboard-specific shortcuts can go here
+	switch msg.String() {
+	case "a":
+		// Pick the account to view and trade in
+		return m, m.openAccountPicker()
+	}
 	return m, nil
 }
 
 func (m *AppModel) handlePortfolioKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Portfolio view-specific shortcuts can go here
+	switch msg.String() {
+	case "a":
+		// Pick the account to view and trade in
+		return m, m.openAccountPicker()
+	}
 	return m, nil
 }
 
@@ -243,7 +309,46 @@ func (m *AppModel) handleMarketDataKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *AppModel) handleOrderHistoryKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Order history view-specific shortcuts can go here
+	switch msg.String() {
+	case "up", "k":
+		if m.OrderCursor > 0 {
+			m.OrderCursor--
+		}
+	case "down", "j":
+		if m.OrderCursor < len(m.visibleOrders())-1 {
+			m.OrderCursor++
+		}
+	case "enter":
+		// Show the selected order with its fills
+		return m, m.openOrderDetail()
+	case "m":
+		// Load the next page of older orders
+		pages := m.OrderPages
+		if m.orderFilterActive() {
+			pages = m.FilterPages
+		}
+		if pages != nil && pages.HasNext() && !m.LoadingOrders {
+			m.Error = ""
+			return m, m.loadOlderOrdersCmd()
+		}
+	case "f":
+		// Edit the server-side order filter
+		if m.Authenticated {
+			m.EditingFilter = true
+		}
+	case "c":
+		// Clear the filter and go back to the recent orders
+		if m.orderFilterActive() {
+			m.clearOrderFilter()
+			m.Error = ""
+		}
+	case "a":
+		// Pick the account whose orders are listed
+		return m, m.openAccountPicker()
+	case "x":
+		// Cancel open orders in bulk
+		m.openBulkCancel()
+	}
 	return m, nil
 }
 
@@ -280,6 +385,10 @@ func (m *AppModel) handleTradingKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleTradingQuantityInput(msg)
 	case TradingStepPrice:
 		return m.handleTradingPriceInput(msg)
+	case TradingStepStopPrice:
+		return m.handleTradingStopPriceInput(msg)
+	case TradingStepTimeInForce:
+		return m.handleTradingTimeInForceSelection(msg)
 	case TradingStepConfirm:
 		return m.handleTradingConfirmation(msg)
 	default:
@@ -330,12 +439,21 @@ func (m *AppModel) handleTradingSymbolInput(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	switch msg.String() {
 	case "enter":
 		if m.TradingForm.Symbol != "" {
+			if err := m.validateSymbol(); err != nil {
+				m.Error = err.Error()
+				return m, nil
+			}
+			m.Error = ""
 			// Fetch live price for the symbol
 			if price, err := m.GetLivePrice(m.TradingForm.Symbol); err == nil {
 				m.TradingForm.CurrentPrice = price
 			}
 			m.TradingStep = TradingStepSide
 			m.TradingForm.Side = "buy" // Default to buy
+			// Retry the order rules if they failed to load at login
+			if m.TradingPairs == nil {
+				return m, m.loadTradingPairsCmd()
+			}
 		}
 		return m, nil
 	case "backspace":
@@ -381,17 +499,27 @@ func (m *AppModel) handleTradingTypeSelection(msg tea.KeyMsg) (tea.Model, tea.Cm
 	switch msg.String() {
 	case "enter":
 		m.TradingStep = TradingStepQuantity
+		if m.TradingForm.TimeInForce == "" {
+			m.TradingForm.TimeInForce = "gtc" // Good Till Cancelled
+		}
 		// Trigger async price fetch if we don't have a current price
-		if m.TradingForm.CurrentPrice == 0 && m.TradingForm.Symbol != "" {
+		if m.TradingForm.CurrentPrice.IsZero() && m.TradingForm.Symbol != "" {
 			return m, m.fetchTradingPriceCmd()
 		}
 		return m, nil
 	case "up", "down":
-		if m.TradingForm.Type == "market" {
-			m.TradingForm.Type = "limit"
+		current := 0
+		for i, orderType := range orderTypes {
+			if orderType.Type == m.TradingForm.Type {
+				current = i
+			}
+		}
+		if msg.String() == "down" {
+			current = (current + 1) % len(orderTypes)
 		} else {
-			m.TradingForm.Type = "market"
+			current = (current + len(orderTypes) - 1) % len(orderTypes)
 		}
+		m.TradingForm.Type = orderTypes[current].Type
 		return m, nil
 	case "backspace":
 		// Go back to side selection
@@ -405,11 +533,15 @@ func (m *AppModel) handleTradingQuantityInput(msg tea.KeyMsg) (tea.Model, tea.Cm
 	switch msg.String() {
 	case "enter":
 		if m.TradingForm.Quantity != "" {
-			if m.TradingForm.Type == "limit" {
-				m.TradingStep = TradingStepPrice
-			} else {
-				m.TradingStep = TradingStepConfirm
+			// Round to the pair's increment and check the size limits
+			if err := m.normalizeQuantity(); err != nil {
+				m.Error = err.Error()
+				return m, nil
 			}
+			m.Error = ""
+			m.nextTradingStep()
+			// Quote the execution price for the entered size
+			return m, m.estimatedPriceCmd()
 		}
 		return m, nil
 	case "backspace":
@@ -419,9 +551,15 @@ func (m *AppModel) handleTradingQuantityInput(msg tea.KeyMsg) (tea.Model, tea.Cm
 			m.updateEstimatedCost()
 		} else {
 			// Go back to type selection if quantity is empty
-			m.TradingStep = TradingStepType
+			m.previousTradingStep()
 		}
 		return m, nil
+	case "tab":
+		// Switch between an asset quantity and a USD amount
+		m.TradingForm.QuoteAmount = !m.TradingForm.QuoteAmount
+		m.TradingForm.Quantity = ""
+		m.updateEstimatedCost()
+		return m, nil
 	default:
 		if len(msg.String()) == 1 {
 			char := msg.String()
@@ -440,15 +578,25 @@ func (m *AppModel) handleTradingPriceInput(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	switch msg.String() {
 	case "enter":
 		if m.TradingForm.Price != "" {
-			m.TradingStep = TradingStepConfirm
+			if err := m.normalizePrice(&m.TradingForm.Price); err != nil {
+				m.Error = err.Error()
+				return m, nil
+			}
+			if err := m.validateLimitPrice(); err != nil {
+				m.Error = err.Error()
+				return m, nil
+			}
+			m.Error = ""
+			m.nextTradingStep()
 		}
 		return m, nil
 	case "backspace":
 		if len(m.TradingForm.Price) > 0 {
 			m.TradingForm.Price = m.TradingForm.Price[:len(m.TradingForm.Price)-1]
+			m.updateEstimatedCost()
 		} else {
-			// Go back to quantity if price is empty
-			m.TradingStep = TradingStepQuantity
+			// Go back to the previous step if price is empty
+			m.previousTradingStep()
 		}
 		return m, nil
 	default:
@@ -465,18 +613,74 @@ func (m *AppModel) handleTradingPriceInput(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	return m, nil
 }
 
+func (m *AppModel) handleTradingStopPriceInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		if m.TradingForm.StopPrice != "" {
+			if err := m.normalizePrice(&m.TradingForm.StopPrice); err != nil {
+				m.Error = err.Error()
+				return m, nil
+			}
+			if err := m.validateStopPrice(); err != nil {
+				m.Error = err.Error()
+				return m, nil
+			}
+			m.Error = ""
+			m.nextTradingStep()
+		}
+		return m, nil
+	case "backspace":
+		if len(m.TradingForm.StopPrice) > 0 {
+			m.TradingForm.StopPrice = m.TradingForm.StopPrice[:len(m.TradingForm.StopPrice)-1]
+			m.updateEstimatedCost()
+		} else {
+			// Go back to quantity if stop price is empty
+			m.previousTradingStep()
+		}
+		return m, nil
+	default:
+		if len(msg.String()) == 1 {
+			char := msg.String()
+			// Allow numbers and decimal point
+			if (char[0] >= '0' && char[0] <= '9') || char[0] == '.' {
+				m.TradingForm.StopPrice += char
+				m.updateEstimatedCost()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *AppModel) handleTradingTimeInForceSelection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.nextTradingStep()
+		return m, nil
+	case "up", "down":
+		if m.TradingForm.TimeInForce == "gfd" {
+			m.TradingForm.TimeInForce = "gtc"
+		} else {
+			m.TradingForm.TimeInForce = "gfd"
+		}
+		return m, nil
+	case "backspace":
+		m.previousTradingStep()
+		return m, nil
+	}
+	return m, nil
+}
+
 func (m *AppModel) handleTradingConfirmation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
-		// Place the order
+		// Place the order, once: Enter is ignored while it is being sent
+		if m.TradingForm.Submitting {
+			return m, nil
+		}
 		return m, m.placeOrderCmd()
 	case "backspace":
 		// Go back to previous step
-		if m.TradingForm.Type == "limit" {
-			m.TradingStep = TradingStepPrice
-		} else {
-			m.TradingStep = TradingStepQuantity
-		}
+		m.previousTradingStep()
 		return m, nil
 	case "esc":
 		// Cancel and return to menu
@@ -492,6 +696,12 @@ func (m *AppModel) menuView() string {
 	title := ui.TitleStyle.Render("🚀 DAZED TRADER 🚀\nRobinhood Terminal Interface")
 
 	var menu string
+	if m.Error != "" {
+		menu += ui.NegativeStyle.Render("❌ "+m.Error) + "\n\n"
+	}
+	if m.LoadingKey {
+		menu += ui.LoadingStyle.Render(fmt.Sprintf("🔄 Loading the API key for profile %s...", m.Profile)) + "\n\n"
+	}
 	menu += "Choose an option:\n\n"
 
 	for i, choice := range m.Choices {
@@ -516,12 +726,19 @@ func (m *AppModel) menuView() string {
 		menu += fmt.Sprintf("%s %s\n", cursor, choice)
 	}
 
+	if panel := m.orderTrackerPanel(); panel != "" {
+		menu += "\n" + panel
+	}
+	if panel := m.orderIntentsPanel(); panel != "" {
+		menu += "\n" + panel
+	}
+
 	authStatus := "🔴 Not Authenticated"
 	if m.Authenticated {
 		authStatus = fmt.Sprintf("🟢 Authenticated as %s", m.Username)
 	}
 
-	footer := ui.InfoStyle.Render(fmt.Sprintf("\nStatus: %s\nPress 'q' to quit • Use ↑↓ to navigate • Enter to select\nShortcuts: 1-Login 2-Dashboard 3-Trading 4-Portfolio", authStatus))
+	footer := ui.InfoStyle.Render(fmt.Sprintf("\nStatus: %s\nPress 'q' to quit • Use ↑↓ to navigate • Enter to select • 'P' to switch profile\nShortcuts: 1-Login 2-Dashboard 3-Trading 4-Portfolio", authStatus))
 
 	return fmt.Sprintf("%s\n\n%s\n%s", title, ui.MenuStyle.Render(menu), footer)
 }
@@ -549,6 +766,7 @@ func (m *AppModel) portfolioView() string {
 		}
 		content.WriteString(ui.InfoStyle.Render(sourceIcon + " Data: " + m.DataSource + "\n\n"))
 	}
+	content.WriteString(m.accountStatus())
 
 	if m.Loading {
 		content.WriteString(ui.LoadingStyle.Render("🔄 Loading positions...\n\n"))
@@ -561,17 +779,17 @@ func (m *AppModel) portfolioView() string {
 
 		for _, pos := range m.Portfolio.Holdings {
 			changePercent := 0.0
-			if pos.CurrentPrice > 0 && pos.Quantity > 0 {
-				previousPrice := pos.CurrentPrice - (pos.DayChange / pos.Quantity)
-				if previousPrice > 0 {
-					changePercent = ((pos.CurrentPrice - previousPrice) / previousPrice) * 100
+			if pos.CurrentPrice.IsPositive() && pos.Quantity.IsPositive() {
+				previousPrice := pos.CurrentPrice.Sub(pos.DayChange.Div(pos.Quantity))
+				if previousPrice.IsPositive() {
+					changePercent = pos.CurrentPrice.Sub(previousPrice).Div(previousPrice).Float64() * 100
 				}
 			}
 
 			// Format with proper padding and color coding
-			content.WriteString(fmt.Sprintf("%-8s %15.4f %18s %18s %15s %12s\n",
+			content.WriteString(fmt.Sprintf("%-8s %15s %18s %18s %15s %12s\n",
 				pos.AssetCode,
-				pos.Quantity,
+				pos.Quantity.StringFixed(4),
 				ui.FormatPrice(pos.CurrentPrice),
 				ui.FormatMarketValue(pos.MarketValue),
 				ui.FormatCurrency(pos.DayChange),
@@ -580,9 +798,9 @@ func (m *AppModel) portfolioView() string {
 		}
 
 		// Calculate total value from holdings
-		totalValue := 0.0
+		totalValue := decimal.Zero
 		for _, holding := range m.Portfolio.Holdings {
-			totalValue += holding.MarketValue
+			totalValue = totalValue.Add(holding.MarketValue)
 		}
 		total := fmt.Sprintf("\nTOTAL PORTFOLIO VALUE: %s", ui.FormatValue(totalValue))
 		content.WriteString(total)
@@ -607,6 +825,7 @@ func (m *AppModel) tradingView() string {
 	if m.Error != "" {
 		content.WriteString(ui.NegativeStyle.Render("❌ " + m.Error + "\n\n"))
 	}
+	content.WriteString(m.orderIntentsPanel())
 
 	if m.TradingForm.Submitting {
 		content.WriteString(ui.LoadingStyle.Render("🔄 Placing order...\n\n"))
@@ -621,14 +840,14 @@ func (m *AppModel) tradingView() string {
 		content.WriteString("Enter crypto symbol (e.g., BTC-USD, ETH-USD):\n")
 		content.WriteString(ui.InputStyle.Render(m.TradingForm.Symbol + "│") + "\n\n")
 		content.WriteString("Popular symbols: BTC-USD, ETH-USD, DOGE-USD, ADA-USD\n")
-		if m.TradingForm.CurrentPrice > 0 {
+		if m.TradingForm.CurrentPrice.IsPositive() {
 			content.WriteString(fmt.Sprintf("\n💰 Current Price: %s", ui.FormatValue(m.TradingForm.CurrentPrice)))
 		}
 
 	case TradingStepSide:
 		content.WriteString("📈 **STEP 2: BUY OR SELL**\n\n")
 		content.WriteString(fmt.Sprintf("Symbol: %s", m.TradingForm.Symbol))
-		if m.TradingForm.CurrentPrice > 0 {
+		if m.TradingForm.CurrentPrice.IsPositive() {
 			content.WriteString(fmt.Sprintf(" | Price: %s", ui.FormatValue(m.TradingForm.CurrentPrice)))
 		}
 		content.WriteString("\n\n")
@@ -644,71 +863,155 @@ func (m *AppModel) tradingView() string {
 	case TradingStepType:
 		content.WriteString("⚙️ **STEP 3: ORDER TYPE**\n\n")
 		content.WriteString(fmt.Sprintf("Symbol: %s | Side: %s", m.TradingForm.Symbol, strings.ToUpper(m.TradingForm.Side)))
-		if m.TradingForm.CurrentPrice > 0 {
+		if m.TradingForm.CurrentPrice.IsPositive() {
 			content.WriteString(fmt.Sprintf(" | Price: %s", ui.FormatValue(m.TradingForm.CurrentPrice)))
 		}
 		content.WriteString("\n\n")
 		content.WriteString("Choose order type:\n")
-		if m.TradingForm.Type == "market" {
-			content.WriteString(ui.SelectedStyle.Render("► MARKET (Execute immediately at current price)") + "\n")
-			content.WriteString(ui.UnselectedStyle.Render("  LIMIT (Set your own price)") + "\n")
-		} else {
-			content.WriteString(ui.UnselectedStyle.Render("  MARKET (Execute immediately at current price)") + "\n")
-			content.WriteString(ui.SelectedStyle.Render("► LIMIT (Set your own price)") + "\n")
+		for _, orderType := range orderTypes {
+			if m.TradingForm.Type == orderType.Type {
+				content.WriteString(ui.SelectedStyle.Render("► "+orderType.Label) + "\n")
+			} else {
+				content.WriteString(ui.UnselectedStyle.Render("  "+orderType.Label) + "\n")
+			}
 		}
 
 	case TradingStepQuantity:
 		content.WriteString("🔢 **STEP 4: QUANTITY**\n\n")
 		content.WriteString(fmt.Sprintf("Symbol: %s | Side: %s | Type: %s",
-			m.TradingForm.Symbol, strings.ToUpper(m.TradingForm.Side), strings.ToUpper(m.TradingForm.Type)))
-		if m.TradingForm.CurrentPrice > 0 {
+			m.TradingForm.Symbol, strings.ToUpper(m.TradingForm.Side), orderTypeName(m.TradingForm.Type)))
+		if m.TradingForm.CurrentPrice.IsPositive() {
 			content.WriteString(fmt.Sprintf(" | Price: %s", ui.FormatValue(m.TradingForm.CurrentPrice)))
 		}
 		content.WriteString("\n\n")
-		content.WriteString("Enter quantity:\n")
-		content.WriteString(ui.InputStyle.Render(m.TradingForm.Quantity + "│") + "\n\n")
-		if m.TradingForm.EstimatedCost > 0 {
+		if m.TradingForm.QuoteAmount {
+			content.WriteString("Enter amount (USD):\n")
+			content.WriteString(ui.InputStyle.Render("$" + m.TradingForm.Quantity + "│") + "\n")
+			content.WriteString("Press Tab to enter an asset quantity instead\n\n")
+		} else {
+			content.WriteString("Enter quantity:\n")
+			content.WriteString(ui.InputStyle.Render(m.TradingForm.Quantity + "│") + "\n")
+			content.WriteString("Press Tab to enter a USD amount instead\n\n")
+		}
+		if pair, ok := m.tradingPair(m.TradingForm.Symbol); ok {
+			content.WriteString(fmt.Sprintf("Order size: %s to %s %s in steps of %s\n",
+				pair.MinOrderSize, pair.MaxOrderSize, pair.AssetCode, pair.AssetIncrement))
+		}
+		if m.hasEstimatedPrice() {
+			content.WriteString(fmt.Sprintf("Quoted Price: %s (spread %s)\n",
+				ui.FormatValue(m.TradingForm.QuotedPrice), ui.FormatValue(m.TradingForm.SpreadCost)))
+		}
+		if m.TradingForm.QuoteAmount && m.TradingForm.EstimatedQuantity.IsPositive() {
+			content.WriteString(fmt.Sprintf("🔢 Estimated Quantity: %s %s\n",
+				m.TradingForm.EstimatedQuantity.StringFixed(8), m.TradingForm.assetCode()))
+		} else if m.TradingForm.EstimatedCost.IsPositive() {
 			content.WriteString(fmt.Sprintf("💰 Estimated Cost: %s\n", ui.FormatValue(m.TradingForm.EstimatedCost)))
 		}
 		if m.Portfolio != nil {
 			content.WriteString(fmt.Sprintf("Available buying power: %s\n", ui.FormatValue(m.Portfolio.BuyingPower)))
 		}
 
+	case TradingStepStopPrice:
+		content.WriteString(fmt.Sprintf("🛑 **STEP %d: STOP PRICE**\n\n", m.tradingStepNumber(TradingStepStopPrice)))
+		content.WriteString(fmt.Sprintf("Symbol: %s | Side: %s | Quantity: %s",
+			m.TradingForm.Symbol, strings.ToUpper(m.TradingForm.Side), m.TradingForm.sizeDisplay()))
+		if m.TradingForm.CurrentPrice.IsPositive() {
+			content.WriteString(fmt.Sprintf(" | Market Price: %s", ui.FormatValue(m.TradingForm.CurrentPrice)))
+		}
+		content.WriteString("\n\n")
+		if m.TradingForm.Side == "sell" {
+			content.WriteString("Enter stop price (USD), below the market price:\n")
+		} else {
+			content.WriteString("Enter stop price (USD), above the market price:\n")
+		}
+		content.WriteString(ui.InputStyle.Render(m.TradingForm.StopPrice + "│") + "\n\n")
+		if m.TradingForm.Type == "stop_loss" {
+			content.WriteString("A market order is sent once the stop price is reached.\n")
+		} else {
+			content.WriteString("A limit order is sent once the stop price is reached.\n")
+		}
+		if m.TradingForm.EstimatedCost.IsPositive() {
+			content.WriteString(fmt.Sprintf("💰 Estimated Cost: %s\n", ui.FormatValue(m.TradingForm.EstimatedCost)))
+		}
+
+	case TradingStepTimeInForce:
+		content.WriteString(fmt.Sprintf("⏱️ **STEP %d: TIME IN FORCE**\n\n", m.tradingStepNumber(TradingStepTimeInForce)))
+		content.WriteString(fmt.Sprintf("Symbol: %s | Side: %s | Type: %s\n\n",
+			m.TradingForm.Symbol, strings.ToUpper(m.TradingForm.Side), orderTypeName(m.TradingForm.Type)))
+		content.WriteString("How long should the order stay open?\n")
+		if m.TradingForm.TimeInForce == "gfd" {
+			content.WriteString(ui.UnselectedStyle.Render("  GTC (Good till canceled)") + "\n")
+			content.WriteString(ui.SelectedStyle.Render("► GFD (Good for the day)") + "\n")
+		} else {
+			content.WriteString(ui.SelectedStyle.Render("► GTC (Good till canceled)") + "\n")
+			content.WriteString(ui.UnselectedStyle.Render("  GFD (Good for the day)") + "\n")
+		}
+
 	case TradingStepPrice:
-		content.WriteString("💰 **STEP 5: LIMIT PRICE**\n\n")
+		content.WriteString(fmt.Sprintf("💰 **STEP %d: LIMIT PRICE**\n\n", m.tradingStepNumber(TradingStepPrice)))
 		content.WriteString(fmt.Sprintf("Symbol: %s | Side: %s | Quantity: %s",
-			m.TradingForm.Symbol, strings.ToUpper(m.TradingForm.Side), m.TradingForm.Quantity))
-		if m.TradingForm.CurrentPrice > 0 {
+			m.TradingForm.Symbol, strings.ToUpper(m.TradingForm.Side), m.TradingForm.sizeDisplay()))
+		if m.TradingForm.CurrentPrice.IsPositive() {
 			content.WriteString(fmt.Sprintf(" | Market Price: %s", ui.FormatValue(m.TradingForm.CurrentPrice)))
 		}
+		if m.TradingForm.StopPrice != "" && m.TradingForm.usesStopPrice() {
+			content.WriteString(fmt.Sprintf(" | Stop: $%s", m.TradingForm.StopPrice))
+		}
 		content.WriteString("\n\n")
 		content.WriteString("Enter limit price (USD):\n")
 		content.WriteString(ui.InputStyle.Render(m.TradingForm.Price + "│") + "\n\n")
-		if m.TradingForm.EstimatedCost > 0 {
+		if m.TradingForm.EstimatedCost.IsPositive() {
 			content.WriteString(fmt.Sprintf("💰 Estimated Cost: %s\n", ui.FormatValue(m.TradingForm.EstimatedCost)))
 		}
 
 	case TradingStepConfirm:
-		content.WriteString("✅ **STEP 6: CONFIRM ORDER**\n\n")
+		content.WriteString(fmt.Sprintf("✅ **STEP %d: CONFIRM ORDER**\n\n", m.tradingStepNumber(TradingStepConfirm)))
 		content.WriteString("Please review your order:\n\n")
+		if account := m.activeAccount(); account != "" {
+			content.WriteString(fmt.Sprintf("Account:     %s\n", account))
+		}
 		content.WriteString(fmt.Sprintf("Symbol:      %s\n", m.TradingForm.Symbol))
 		content.WriteString(fmt.Sprintf("Side:        %s\n", strings.ToUpper(m.TradingForm.Side)))
-		content.WriteString(fmt.Sprintf("Type:        %s\n", strings.ToUpper(m.TradingForm.Type)))
-		content.WriteString(fmt.Sprintf("Quantity:    %s\n", m.TradingForm.Quantity))
-		if m.TradingForm.CurrentPrice > 0 {
+		content.WriteString(fmt.Sprintf("Type:        %s\n", orderTypeName(m.TradingForm.Type)))
+		if m.TradingForm.QuoteAmount {
+			content.WriteString(fmt.Sprintf("Amount:      %s\n", m.TradingForm.sizeDisplay()))
+			if m.TradingForm.EstimatedQuantity.IsPositive() {
+				content.WriteString(fmt.Sprintf("Est. Qty:    %s %s\n", m.TradingForm.EstimatedQuantity.StringFixed(8), m.TradingForm.assetCode()))
+			}
+		} else {
+			content.WriteString(fmt.Sprintf("Quantity:    %s\n", m.TradingForm.Quantity))
+		}
+		if m.TradingForm.CurrentPrice.IsPositive() {
 			content.WriteString(fmt.Sprintf("Market Price: %s\n", ui.FormatValue(m.TradingForm.CurrentPrice)))
 		}
-		if m.TradingForm.Type == "limit" {
-			if price, err := strconv.ParseFloat(m.TradingForm.Price, 64); err == nil {
+		if m.TradingForm.usesStopPrice() {
+			if price, err := decimal.Parse(m.TradingForm.StopPrice); err == nil {
+				content.WriteString(fmt.Sprintf("Stop Price:   %s\n", ui.FormatValue(price)))
+			} else {
+				content.WriteString(fmt.Sprintf("Stop Price:   $%s\n", m.TradingForm.StopPrice))
+			}
+		}
+		if m.TradingForm.usesLimitPrice() {
+			if price, err := decimal.Parse(m.TradingForm.Price); err == nil {
 				content.WriteString(fmt.Sprintf("Limit Price:  %s\n", ui.FormatValue(price)))
 			} else {
 				content.WriteString(fmt.Sprintf("Limit Price:  $%s\n", m.TradingForm.Price))
 			}
 		}
-		if m.TradingForm.EstimatedCost > 0 {
-			content.WriteString(fmt.Sprintf("💰 Est. Cost:  %s\n", ui.FormatValue(m.TradingForm.EstimatedCost)))
+		if m.TradingForm.Type != "market" {
+			content.WriteString(fmt.Sprintf("Time in Force: %s\n", strings.ToUpper(m.TradingForm.TimeInForce)))
+		}
+		if m.hasEstimatedPrice() {
+			content.WriteString(fmt.Sprintf("Quoted Price: %s\n", ui.FormatValue(m.TradingForm.QuotedPrice)))
+			content.WriteString(fmt.Sprintf("Spread Cost:  %s\n", ui.FormatValue(m.TradingForm.SpreadCost)))
+		}
+		if m.TradingForm.EstimatedCost.IsPositive() {
+			content.WriteString(fmt.Sprintf("💰 Est. Total: %s\n", ui.FormatValue(m.TradingForm.EstimatedCost)))
 		}
 		content.WriteString("\n")
+		if m.TradingForm.Intent != nil {
+			content.WriteString(ui.InfoStyle.Render("🔁 Retrying reuses order ID "+m.TradingForm.Intent.ClientOrderID+", so it can't be placed twice") + "\n")
+		}
 		content.WriteString(ui.PositiveStyle.Render("Press ENTER to place order") + "\n")
 		content.WriteString(ui.NegativeStyle.Render("Press ESC to cancel") + "\n")
 
@@ -720,6 +1023,7 @@ func (m *AppModel) tradingView() string {
 		content.WriteString("2. ETH-USD - Ethereum\n")
 		content.WriteString("3. DOGE-USD - Dogecoin\n")
 		content.WriteString("4. ADA-USD - Cardano\n\n")
+		content.WriteString(m.accountStatus())
 		if m.Portfolio != nil {
 			content.WriteString(fmt.Sprintf("💰 Available buying power: %s\n\n", ui.FormatValue(m.Portfolio.BuyingPower)))
 		}
@@ -743,7 +1047,11 @@ KEYBOARD SHORTCUTS:
   Esc         - Go back / Return to main menu
   Q           - Quit application (from main menu)
   R/F5        - Refresh data (on dashboard)
+  A           - Switch account (dashboard, positions, order history)
+  X           - Cancel open orders in bulk (order history)
+  P           - Switch profile (main menu)
   Tab         - Toggle password visibility (login)
+  Ctrl+G      - Generate a key pair for a new API key (login)
 
 NAVIGATION:
   1 - Quick login
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"dazedtrader/decimal"
+	"strings"
+	"testing"
+)
+
+func TestTradingViewEstimatedQuantity(t *testing.T) {
+	for _, step := range []int{TradingStepQuantity, TradingStepConfirm} {
+		m := &AppModel{
+			State:         StateTrading,
+			Authenticated: true,
+			TradingStep:   step,
+			TradingForm: TradingForm{
+				Symbol:            "BTC-USD",
+				Side:              "buy",
+				Type:              "market",
+				QuoteAmount:       true,
+				Quantity:          "250",
+				EstimatedQuantity: decimal.MustParse("0.0025"),
+			},
+		}
+
+		view := m.tradingView()
+		if !strings.Contains(view, "0.00250000 BTC") {
+			t.Errorf("step %d: estimated quantity not rendered as 0.00250000 BTC:\n%s", step, view)
+		}
+		if strings.Contains(view, "%!") {
+			t.Errorf("step %d: view has a formatting error:\n%s", step, view)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"crypto/ed25519"
+	"dazedtrader/api"
+	"dazedtrader/auth"
+	"dazedtrader/ui"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyGen holds the key generation screen: the public key to create the API
+// key with, and the API key typed in once Robinhood has issued it
+type KeyGen struct {
+	PublicKey string
+	APIKey    string
+	Copied    bool
+
+	privateKey ed25519.PrivateKey
+	passphrase []byte // the credentials are saved with the key's passphrase
+}
+
+// openKeyGen asks for a passphrase to generate a key pair for the active
+// profile with, or to unlock the one generated earlier that is still
+// waiting for its API key
+func (m *AppModel) openKeyGen() {
+	if m.ephemeralCredentials || m.credentials == nil || m.credentials.ReadOnly() {
+		m.Error = "Key pairs can only be generated for profiles whose key is stored by the app"
+		return
+	}
+
+	pending, err := auth.LoadPendingKey(m.Profile)
+	if err != nil {
+		m.Error = fmt.Sprintf("Failed to load generated key: %v", err)
+		return
+	}
+
+	m.Error = ""
+	if pending == nil {
+		m.openPassphrase(passphraseGenerate, nil, "")
+		return
+	}
+	m.openPassphrase(passphrasePending, nil, "")
+	m.Passphrase.pending = pending
+}
+
+// showKeyGen shows the public key of a generated key pair and asks for the
+// API key created with it
+func (m *AppModel) showKeyGen(publicKey string, privateKey ed25519.PrivateKey, passphrase []byte) {
+	m.KeyGen = KeyGen{PublicKey: publicKey, privateKey: privateKey, passphrase: passphrase}
+	m.Passphrase = PassphraseForm{}
+	m.State = StateKeyGen
+}
+
+// CompleteKeyGen logs in with the entered API key and the generated private
+// key, then saves them like any other API key and drops the pending key
+func (m *AppModel) CompleteKeyGen() error {
+	if m.Loading {
+		return nil // Already processing
+	}
+
+	keyGen := &m.KeyGen
+	apiKey := strings.TrimSpace(keyGen.APIKey)
+	if strings.Contains(apiKey, ":") {
+		m.Error = "Enter the API key only, its private key was generated here"
+		return nil
+	}
+
+	credentials := api.FormatCredentials(apiKey, keyGen.privateKey)
+	if !m.verifyCredentials(credentials) {
+		return nil
+	}
+
+	// Logged in either way; a key that couldn't be saved stays pending so
+	// setup can be finished again
+	m.State = StateMenu
+	expiresAt := time.Now().Add(storedKeyLifetime).Unix()
+	if err := m.credentials.Save(credentials, m.Username, expiresAt, keyGen.passphrase); err != nil {
+		m.Error = fmt.Sprintf("Failed to save API key: %v", err)
+	} else if err := auth.ClearPendingKey(m.Profile); err != nil {
+		m.Error = fmt.Sprintf("API key saved, but the generated key could not be removed: %v", err)
+	}
+	m.KeyGen = KeyGen{}
+
+	return nil
+}
+
+func (m *AppModel) completeKeyGenCmd() tea.Cmd {
+	return func() tea.Msg {
+		err := m.CompleteKeyGen()
+		return apiKeySetupCompletedMsg{err: err}
+	}
+}
+
+// handleKeyGenKeys takes all keys on the key generation screen so the API
+// key can be typed freely
+func (m *AppModel) handleKeyGenKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keyGen := &m.KeyGen
+	if m.Loading {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		// The generated key stays saved; Ctrl+G on the login screen continues
+		m.KeyGen = KeyGen{}
+		m.State = StateLogin
+		m.Error = ""
+
+	case "enter":
+		if keyGen.APIKey != "" {
+			m.Error = ""
+			return m, m.completeKeyGenCmd()
+		}
+
+	case "ctrl+y":
+		if err := clipboard.WriteAll(keyGen.PublicKey); err != nil {
+			m.Error = fmt.Sprintf("Failed to copy the public key: %v", err)
+		} else {
+			keyGen.Copied = true
+			m.Error = ""
+		}
+
+	case "ctrl+v":
+		// Paste from clipboard
+		clipboardText, err := clipboard.ReadAll()
+		if err == nil && clipboardText != "" {
+			clipboardText = strings.ReplaceAll(clipboardText, "\n", "")
+			clipboardText = strings.ReplaceAll(clipboardText, "\r", "")
+			keyGen.APIKey = strings.TrimSpace(clipboardText)
+		}
+
+	case "backspace":
+		if len(keyGen.APIKey) > 0 {
+			keyGen.APIKey = keyGen.APIKey[:len(keyGen.APIKey)-1]
+		}
+
+	case "ctrl+a":
+		keyGen.APIKey = ""
+
+	default:
+		if len(msg.String()) == 1 {
+			char := msg.String()
+			if char[0] >= 32 && char[0] <= 126 { // Printable ASCII
+				keyGen.APIKey += char
+			}
+		}
+	}
+	return m, nil
+}
+
+// keyGenView renders the public key to register and the API key form
+func (m *AppModel) keyGenView() string {
+	title := ui.HeaderStyle.Render("🔑 GENERATE KEY PAIR")
+	keyGen := m.KeyGen
+
+	var content strings.Builder
+
+	if m.Error != "" {
+		content.WriteString(ui.NegativeStyle.Render("❌ " + m.Error + "\n\n"))
+	}
+
+	if m.Loading {
+		content.WriteString("🔄 Verifying API key...\n")
+		return fmt.Sprintf("%s\n%s\n", title, ui.MenuStyle.Render(content.String()))
+	}
+
+	content.WriteString("1. Create an API key in your Robinhood account with this public key:\n")
+	content.WriteString("   (https://docs.robinhood.com/crypto/trading/)\n\n")
+	content.WriteString(ui.PositiveStyle.Render(keyGen.PublicKey) + "\n")
+	if keyGen.Copied {
+		content.WriteString("✅ Copied to the clipboard\n")
+	}
+	content.WriteString("\nThe private key is saved encrypted and never leaves this computer.\n\n")
+
+	content.WriteString("2. Enter the API key Robinhood shows for it:\n")
+	content.WriteString(ui.InputStyle.Render(keyGen.APIKey+"│") + "\n\n")
+	content.WriteString("Ctrl+A to clear all text\n")
+
+	footer := ui.InfoStyle.Render("Ctrl+Y to copy the public key • Ctrl+V to paste • Enter to verify and save • 'Esc' to finish later, the key stays saved")
+
+	return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
+}
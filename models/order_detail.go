@@ -0,0 +1,237 @@
+package models
+
+import (
+	"dazedtrader/api"
+	"dazedtrader/decimal"
+	"dazedtrader/ui"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// orderDetailLoadedMsg is sent once the order shown on the detail screen
+// has been fetched
+type orderDetailLoadedMsg struct{ err error }
+
+// selectedOrder returns the order under the order history cursor
+func (m *AppModel) selectedOrder() (CryptoOrder, bool) {
+	orders := m.visibleOrders()
+	if len(orders) == 0 {
+		return CryptoOrder{}, false
+	}
+	if m.OrderCursor >= len(orders) {
+		m.OrderCursor = len(orders) - 1
+	}
+	return orders[m.OrderCursor], true
+}
+
+// visibleOrders returns the orders listed on the order history screen: the
+// filter results, or the recent orders followed by any older pages
+func (m *AppModel) visibleOrders() []CryptoOrder {
+	if m.orderFilterActive() {
+		return m.FilteredOrders
+	}
+	if m.Portfolio == nil {
+		return nil
+	}
+	return append(append([]CryptoOrder{}, m.Portfolio.Orders...), m.OlderOrders...)
+}
+
+// openOrderDetail shows the detail screen for the selected order and fetches
+// its fills
+func (m *AppModel) openOrderDetail() tea.Cmd {
+	order, ok := m.selectedOrder()
+	if !ok {
+		return nil
+	}
+
+	m.DetailOrderID = order.ID
+	m.DetailOrder = nil
+	m.DetailQuote = nil
+	m.Error = ""
+	m.State = StateOrderDetail
+	return m.loadOrderDetailCmd()
+}
+
+// LoadOrderDetail fetches the detail order with its executions, and a quote
+// for the symbol to estimate the spread paid on each fill
+func (m *AppModel) LoadOrderDetail() error {
+	if !m.Authenticated || m.CryptoClient == nil || m.DetailOrderID == "" {
+		return nil
+	}
+
+	m.LoadingDetail = true
+	defer func() {
+		m.LoadingDetail = false
+	}()
+
+	orderID := m.DetailOrderID
+	ctx := m.requestContext()
+	order, err := m.CryptoClient.GetCryptoOrderContext(ctx, orderID)
+	if err != nil && ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		m.Error = describeAPIError("Failed to load order", err)
+		return err
+	}
+
+	// The spread estimate is optional, the fills are shown without it
+	var quote *api.BestBidAsk
+	if quotes, err := m.CryptoClient.GetBestBidAskContext(ctx, []string{order.Symbol}); err == nil && len(quotes) > 0 {
+		quote = &quotes[0]
+	}
+
+	// Another order may have been opened while this one was loading
+	if m.DetailOrderID != orderID {
+		return nil
+	}
+	m.DetailOrder = order
+	m.DetailQuote = quote
+	return nil
+}
+
+func (m *AppModel) loadOrderDetailCmd() tea.Cmd {
+	return func() tea.Msg {
+		err := m.LoadOrderDetail()
+		return orderDetailLoadedMsg{err: err}
+	}
+}
+
+// detailSpreadRate returns the current spread for the detail order's side as
+// a fraction of the mid price, or zero without a quote
+func (m *AppModel) detailSpreadRate() decimal.Decimal {
+	quote := m.DetailQuote
+	if quote == nil || m.DetailOrder == nil || !quote.Price.IsPositive() {
+		return decimal.Zero
+	}
+	if m.DetailOrder.Side == "sell" {
+		return quote.Price.Sub(quote.BidPrice).Div(quote.Price)
+	}
+	return quote.AskPrice.Sub(quote.Price).Div(quote.Price)
+}
+
+// executionSpread estimates the USD paid to the spread on a fill, taking the
+// mid price as the fill price with rate removed
+func executionSpread(execution api.Execution, side string, rate decimal.Decimal) decimal.Decimal {
+	one := decimal.NewFromInt(1)
+	factor := one.Add(rate)
+	if side == "sell" {
+		factor = one.Sub(rate)
+	}
+	mid := execution.EffectivePrice.Div(factor)
+	return execution.EffectivePrice.Sub(mid).Abs().Mul(execution.Quantity)
+}
+
+// orderDetailView renders the detail screen: the order summary followed by
+// each fill with its price, quantity, time and estimated spread
+func (m *AppModel) orderDetailView() string {
+	title := ui.HeaderStyle.Render("🧾 ORDER DETAIL")
+	footer := ui.InfoStyle.Render("Press 'R' or 'F5' to refresh • 'Esc' to return to order history")
+
+	var content strings.Builder
+
+	if m.Error != "" {
+		content.WriteString(ui.NegativeStyle.Render("❌ " + m.Error + "\n\n"))
+	}
+
+	order := m.DetailOrder
+	if order == nil {
+		if m.LoadingDetail {
+			content.WriteString(ui.LoadingStyle.Render("🔄 Loading order...\n"))
+		} else {
+			content.WriteString("📊 Order not loaded.\nPress 'R' or 'F5' to retry.\n")
+		}
+		return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
+	}
+
+	asset := strings.TrimSuffix(order.Symbol, "-USD")
+	side := strings.ToUpper(order.Side)
+	if order.Side == "buy" {
+		side = ui.PositiveStyle.Render(side)
+	} else {
+		side = ui.NegativeStyle.Render(side)
+	}
+
+	ordered := fmt.Sprintf("%s %s", order.Quantity, asset)
+	if order.QuoteAmount.IsPositive() {
+		ordered = ui.FormatValue(order.QuoteAmount)
+	}
+
+	content.WriteString(fmt.Sprintf("Order:    %s\n", order.ID))
+	content.WriteString(fmt.Sprintf("Symbol:   %-12s Side: %s   Type: %s\n", order.Symbol, side, strings.ToUpper(strings.ReplaceAll(order.Type, "_", " "))))
+	content.WriteString(fmt.Sprintf("State:    %s\n", strings.ReplaceAll(order.State, "_", " ")))
+	content.WriteString(fmt.Sprintf("Created:  %s\n", order.CreatedAt))
+	content.WriteString(fmt.Sprintf("Updated:  %s\n", order.UpdatedAt))
+	content.WriteString(fmt.Sprintf("Ordered:  %s\n", ordered))
+	if order.FilledAssetQuantity.IsPositive() {
+		content.WriteString(fmt.Sprintf("Filled:   %s %s at avg %s\n", order.FilledAssetQuantity, asset, ui.FormatPrice(order.AveragePrice)))
+	}
+	content.WriteString("\n")
+
+	if len(order.Executions) == 0 {
+		content.WriteString("📊 No fills yet.\n")
+		return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
+	}
+
+	rate := m.detailSpreadRate()
+
+	content.WriteString("💱 FILLS\n")
+	content.WriteString("═════════\n")
+	content.WriteString("Time                 Quantity          Price            Value           Spread (est. at current spread)\n")
+	content.WriteString("───────────────────────────────────────────────────────────────────────────────────────────────────────\n")
+
+	totalQuantity, totalValue, totalSpread := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, execution := range order.Executions {
+		timestamp := execution.Timestamp
+		if len(timestamp) > 19 {
+			timestamp = strings.Replace(timestamp[:19], "T", " ", 1)
+		}
+
+		spreadStr := "—"
+		if rate.IsPositive() {
+			spread := executionSpread(execution, order.Side, rate)
+			totalSpread = totalSpread.Add(spread)
+			spreadStr = ui.FormatValue(spread)
+		}
+
+		totalQuantity = totalQuantity.Add(execution.Quantity)
+		totalValue = totalValue.Add(execution.Notional())
+
+		content.WriteString(fmt.Sprintf("%-19s  %-16s  %-15s  %-14s  %s\n",
+			timestamp,
+			execution.Quantity.String(),
+			ui.FormatPrice(execution.EffectivePrice),
+			ui.FormatValue(execution.Notional()),
+			spreadStr,
+		))
+	}
+
+	content.WriteString("───────────────────────────────────────────────────────────────────────────────────────────────────────\n")
+	totalSpreadStr := "—"
+	if rate.IsPositive() {
+		totalSpreadStr = ui.FormatValue(totalSpread)
+	}
+	fills := fmt.Sprintf("Total (%d fills)", len(order.Executions))
+	if len(order.Executions) == 1 {
+		fills = "Total (1 fill)"
+	}
+	content.WriteString(fmt.Sprintf("%-19s  %-16s  %-15s  %-14s  %s\n",
+		fills,
+		totalQuantity.String(),
+		"",
+		ui.FormatValue(totalValue),
+		totalSpreadStr,
+	))
+
+	content.WriteString("\n")
+	if rate.IsPositive() {
+		content.WriteString(ui.DisabledStyle.Render(fmt.Sprintf(
+			"Spread estimated from the current %.2f%% %s spread, not the one at fill time; effective prices include it",
+			rate.Float64()*100, order.Side)) + "\n")
+	}
+	content.WriteString(ui.DisabledStyle.Render("Fees are not reported per fill and are not included above") + "\n")
+
+	return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
+}
@@ -0,0 +1,258 @@
+package models
+
+import (
+	"dazedtrader/api"
+	"dazedtrader/ui"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Choices offered by the order history filter, "" meaning any
+var (
+	filterSides  = []string{"", "buy", "sell"}
+	filterStates = []string{"", "open", "partially_filled", "filled", "canceled", "failed"}
+	filterTypes  = []string{"", "market", "limit", "stop_loss", "stop_limit"}
+	filterRanges = []struct {
+		Label  string
+		Within time.Duration
+	}{
+		{"Any time", 0},
+		{"Last 24 hours", 24 * time.Hour},
+		{"Last 7 days", 7 * 24 * time.Hour},
+		{"Last 30 days", 30 * 24 * time.Hour},
+		{"Last 90 days", 90 * 24 * time.Hour},
+		{"Last year", 365 * 24 * time.Hour},
+	}
+)
+
+// Rows of the filter form, in display order
+const (
+	filterRowSymbol = iota
+	filterRowSide
+	filterRowState
+	filterRowType
+	filterRowCreated
+	filterRowUpdated
+	filterRowCount
+)
+
+// OrderFilterForm holds the order history filter while it is being edited.
+// Apart from Symbol, fields are indexes into the filter choices above.
+type OrderFilterForm struct {
+	Cursor  int
+	Symbol  string
+	Side    int
+	State   int
+	Type    int
+	Created int
+	Updated int
+}
+
+// toFilter converts the form into an API filter relative to now
+func (f OrderFilterForm) toFilter(now time.Time) api.OrderFilter {
+	filter := api.OrderFilter{
+		Symbol: f.Symbol,
+		Side:   filterSides[f.Side],
+		State:  filterStates[f.State],
+		Type:   filterTypes[f.Type],
+	}
+	if within := filterRanges[f.Created].Within; within > 0 {
+		filter.CreatedAfter = now.Add(-within)
+	}
+	if within := filterRanges[f.Updated].Within; within > 0 {
+		filter.UpdatedAfter = now.Add(-within)
+	}
+	if !filter.IsZero() {
+		filter.Limit = 20
+	}
+	return filter
+}
+
+// summary describes the active filters, e.g. "BTC-USD · sell · Last 30 days"
+func (f OrderFilterForm) summary() string {
+	var parts []string
+	if f.Symbol != "" {
+		parts = append(parts, f.Symbol)
+	}
+	for _, choice := range []string{filterSides[f.Side], filterStates[f.State], filterTypes[f.Type]} {
+		if choice != "" {
+			parts = append(parts, strings.ReplaceAll(choice, "_", " "))
+		}
+	}
+	if f.Created > 0 {
+		parts = append(parts, "created "+strings.ToLower(filterRanges[f.Created].Label))
+	}
+	if f.Updated > 0 {
+		parts = append(parts, "updated "+strings.ToLower(filterRanges[f.Updated].Label))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// cycle moves the selected choice of the current row by delta
+func (f *OrderFilterForm) cycle(delta int) {
+	step := func(value, count int) int {
+		return (value + delta + count) % count
+	}
+	switch f.Cursor {
+	case filterRowSide:
+		f.Side = step(f.Side, len(filterSides))
+	case filterRowState:
+		f.State = step(f.State, len(filterStates))
+	case filterRowType:
+		f.Type = step(f.Type, len(filterTypes))
+	case filterRowCreated:
+		f.Created = step(f.Created, len(filterRanges))
+	case filterRowUpdated:
+		f.Updated = step(f.Updated, len(filterRanges))
+	}
+}
+
+// orderFilterActive reports whether the order history is showing filtered results
+func (m *AppModel) orderFilterActive() bool {
+	return !m.OrderFilter.IsZero()
+}
+
+// handleOrderFilterKeys edits the filter form. Enter applies it, Esc closes
+// the form without changing the current filter.
+func (m *AppModel) handleOrderFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := &m.FilterForm
+
+	switch msg.String() {
+	case "esc":
+		m.EditingFilter = false
+		return m, nil
+	case "enter":
+		m.EditingFilter = false
+		return m, m.applyOrderFilter()
+	case "up":
+		form.Cursor = (form.Cursor + filterRowCount - 1) % filterRowCount
+	case "down", "tab":
+		form.Cursor = (form.Cursor + 1) % filterRowCount
+	case "left":
+		form.cycle(-1)
+	case "right", " ":
+		form.cycle(1)
+	case "backspace":
+		if form.Cursor == filterRowSymbol && len(form.Symbol) > 0 {
+			form.Symbol = form.Symbol[:len(form.Symbol)-1]
+		}
+	default:
+		if form.Cursor == filterRowSymbol && len(msg.String()) == 1 {
+			char := msg.String()[0]
+			// Allow letters, numbers, and hyphens for crypto symbols
+			if (char >= 'A' && char <= 'Z') || (char >= 'a' && char <= 'z') ||
+				(char >= '0' && char <= '9') || char == '-' {
+				form.Symbol += strings.ToUpper(string(char))
+			}
+		}
+	}
+	return m, nil
+}
+
+// applyOrderFilter switches the order history to the filter form's results,
+// or back to the recent orders when the form is empty
+func (m *AppModel) applyOrderFilter() tea.Cmd {
+	m.OrderFilter = m.FilterForm.toFilter(time.Now())
+	m.FilteredOrders = nil
+	m.FilterPages = nil
+	m.OrderCursor = 0
+	m.Error = ""
+
+	if !m.orderFilterActive() {
+		return nil
+	}
+	return m.loadFilteredOrdersCmd()
+}
+
+// clearOrderFilter drops the filter and shows the recent orders again
+func (m *AppModel) clearOrderFilter() {
+	m.FilterForm = OrderFilterForm{}
+	m.OrderFilter = api.OrderFilter{}
+	m.FilteredOrders = nil
+	m.FilterPages = nil
+	m.OrderCursor = 0
+}
+
+// LoadFilteredOrders fetches the first page of orders matching OrderFilter
+func (m *AppModel) LoadFilteredOrders() error {
+	if !m.Authenticated || m.CryptoClient == nil || !m.orderFilterActive() {
+		return nil
+	}
+
+	m.LoadingOrders = true
+	defer func() {
+		m.LoadingOrders = false
+	}()
+
+	filter := m.OrderFilter
+	pages := m.CryptoClient.CryptoOrdersPages(filter)
+	ctx := m.requestContext()
+	orders, err := pages.NextContext(ctx)
+	if err != nil && ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		m.Error = describeAPIError("Failed to load orders", err)
+		return err
+	}
+
+	// The filter may have changed while this page was loading
+	if m.OrderFilter != filter {
+		return nil
+	}
+
+	m.FilterPages = pages
+	m.FilteredOrders = nil
+	for _, order := range orders {
+		m.FilteredOrders = append(m.FilteredOrders, convertCryptoOrder(order))
+	}
+	return nil
+}
+
+func (m *AppModel) loadFilteredOrdersCmd() tea.Cmd {
+	return func() tea.Msg {
+		err := m.LoadFilteredOrders()
+		return olderOrdersLoadedMsg{err: err}
+	}
+}
+
+// orderFilterView renders the filter form
+func (m *AppModel) orderFilterView() string {
+	form := m.FilterForm
+
+	rows := []struct {
+		label string
+		value string
+	}{
+		{"Symbol", form.Symbol + "│"},
+		{"Side", filterChoiceLabel(filterSides[form.Side])},
+		{"State", filterChoiceLabel(filterStates[form.State])},
+		{"Type", filterChoiceLabel(filterTypes[form.Type])},
+		{"Created", filterRanges[form.Created].Label},
+		{"Updated", filterRanges[form.Updated].Label},
+	}
+
+	var view strings.Builder
+	view.WriteString("🔍 FILTER ORDERS\n")
+	for i, row := range rows {
+		line := fmt.Sprintf("%-8s %s", row.label+":", row.value)
+		if i == form.Cursor {
+			view.WriteString(ui.SelectedStyle.Render("► "+line) + "\n")
+		} else {
+			view.WriteString(ui.UnselectedStyle.Render("  "+line) + "\n")
+		}
+	}
+	view.WriteString("↑↓ select • ←→ change • type a symbol • Enter apply • Esc close\n\n")
+	return view.String()
+}
+
+// filterChoiceLabel formats a filter choice for display
+func filterChoiceLabel(choice string) string {
+	if choice == "" {
+		return "Any"
+	}
+	return strings.ReplaceAll(choice, "_", " ")
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"dazedtrader/api"
+	"dazedtrader/auth"
+	"dazedtrader/ui"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// An intent whose order can't be found is taken as never placed once it
+	// is this old, since no attempt to send it can still be in flight
+	intentSettleTime = 5 * time.Minute
+
+	// How far before an intent was recorded to search for its order, to
+	// allow for the local clock running ahead of Robinhood's
+	intentLookback = 5 * time.Minute
+)
+
+// outcomeUnknownError is returned when an order may or may not have reached
+// Robinhood, e.g. after a timeout or a server error
+type outcomeUnknownError struct {
+	err error
+}
+
+func (e *outcomeUnknownError) Error() string {
+	return "order outcome unknown: " + e.err.Error()
+}
+
+func (e *outcomeUnknownError) Unwrap() error {
+	return e.err
+}
+
+// orderOutcomeUnknown reports whether a failed submission may still have
+// created the order: Robinhood either never answered or failed with a 5xx.
+// A request the signer refused was never sent.
+func orderOutcomeUnknown(err error) bool {
+	if api.IsSignError(err) {
+		return false
+	}
+	var apiErr *api.APIError
+	return !errors.As(err, &apiErr) || api.IsServerError(err)
+}
+
+// keyFingerprint identifies an API key in the pending orders file without
+// storing the key itself
+func keyFingerprint(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// newOrderIntent records params under a fresh client order ID
+func (m *AppModel) newOrderIntent(params api.OrderParams) auth.OrderIntent {
+	return auth.OrderIntent{
+		ClientOrderID: params.ClientOrderID,
+		KeyID:         keyFingerprint(m.CryptoClient.APIKey),
+		AccountNumber: params.AccountNumber,
+		Side:          params.Side,
+		Type:          params.Type,
+		Symbol:        params.Symbol,
+		Quantity:      params.Quantity,
+		QuoteAmount:   params.QuoteAmount,
+		LimitPrice:    params.LimitPrice,
+		StopPrice:     params.StopPrice,
+		TimeInForce:   params.TimeInForce,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// pendingIntents returns the intents sent with the current API key whose
+// outcome is still unknown, leaving out the one being submitted right now
+func (m *AppModel) pendingIntents() []auth.OrderIntent {
+	if m.intents == nil || m.CryptoClient == nil {
+		return nil
+	}
+
+	keyID := keyFingerprint(m.CryptoClient.APIKey)
+	var pending []auth.OrderIntent
+	for _, intent := range m.intents.List() {
+		if intent.KeyID == keyID && intent.ClientOrderID != m.submittingIntent {
+			pending = append(pending, intent)
+		}
+	}
+	return pending
+}
+
+// findIntentOrder looks for the order placed for intent in its account's
+// history, returning nil when there is none
+func (m *AppModel) findIntentOrder(ctx context.Context, intent auth.OrderIntent) (*api.CryptoOrder, error) {
+	pages := m.CryptoClient.CryptoOrdersPages(api.OrderFilter{
+		AccountNumber: intent.AccountNumber,
+		CreatedAfter:  intent.CreatedAt.Add(-intentLookback),
+	})
+	for pages.HasNext() {
+		orders, err := pages.NextContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := range orders {
+			if orders[i].ClientOrderID == intent.ClientOrderID {
+				return &orders[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// reconcileOrderIntents settles the pending intents against the order
+// history: an intent whose order exists was placed, one without an order
+// after intentSettleTime never reached Robinhood. Intents that can't be
+// looked up stay pending until the next refresh.
+func (m *AppModel) reconcileOrderIntents(ctx context.Context) {
+	for _, intent := range m.pendingIntents() {
+		order, err := m.findIntentOrder(ctx, intent)
+		if err != nil {
+			continue
+		}
+
+		if order != nil {
+			m.Notice = fmt.Sprintf("🔁 Earlier %s %s order went through and is %s",
+				strings.ToUpper(intent.Side), intent.Symbol, strings.ReplaceAll(order.State, "_", " "))
+		} else if time.Since(intent.CreatedAt) >= intentSettleTime {
+			m.Notice = fmt.Sprintf("⚠ Earlier %s %s order never reached Robinhood and was not placed",
+				strings.ToUpper(intent.Side), intent.Symbol)
+		} else {
+			continue
+		}
+
+		// If this fails the intent is simply settled again on the next refresh
+		m.intents.Remove(intent.ClientOrderID)
+	}
+}
+
+// intentSize describes how large an intent's order is
+func intentSize(intent auth.OrderIntent) string {
+	if intent.QuoteAmount != "" {
+		return "$" + intent.QuoteAmount + " of"
+	}
+	return intent.Quantity
+}
+
+// orderIntentsPanel lists the submitted orders whose outcome is still
+// unknown, so they can be checked before trading again
+func (m *AppModel) orderIntentsPanel() string {
+	intents := m.pendingIntents()
+	if len(intents) == 0 {
+		return ""
+	}
+
+	var panel strings.Builder
+	panel.WriteString(ui.NegativeStyle.Render(fmt.Sprintf("❓ Outcome unknown for %d submitted order(s), rechecked on every refresh:", len(intents))) + "\n")
+	for _, intent := range intents {
+		panel.WriteString(fmt.Sprintf("   %s %s %s %s • sent %s • order ID %s\n",
+			strings.ToUpper(intent.Side),
+			intentSize(intent),
+			intent.Symbol,
+			orderTypeName(intent.Type),
+			intent.CreatedAt.Format("Jan 2 3:04 PM"),
+			intent.ClientOrderID,
+		))
+	}
+	return panel.String() + "\n"
+}
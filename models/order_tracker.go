@@ -0,0 +1,191 @@
+package models
+
+import (
+	"context"
+	"dazedtrader/api"
+	"dazedtrader/decimal"
+	"dazedtrader/ui"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// How often a tracked order is polled
+	orderPollInterval = 2 * time.Second
+
+	// Stop tracking an order that is still resting after this long,
+	// e.g. a limit order away from the market
+	orderTrackTimeout = 2 * time.Minute
+)
+
+// OrderTracker follows a newly submitted order until it reaches a terminal state
+type OrderTracker struct {
+	OrderID      string
+	Symbol       string
+	Side         string
+	Type         string
+	Quantity     decimal.Decimal // Ordered quantity
+	Filled       decimal.Decimal // Quantity executed so far
+	AveragePrice decimal.Decimal
+	State        string
+	LastError    string
+	StartedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// orderProgressMsg carries the latest poll of the tracked order
+type orderProgressMsg struct {
+	orderID string
+	order   *api.CryptoOrder
+	err     error
+}
+
+// orderCompletedMsg is sent once the tracked order is filled, canceled or failed
+type orderCompletedMsg struct {
+	order api.CryptoOrder
+}
+
+// trackOrder starts following order and returns the first poll
+func (m *AppModel) trackOrder(order *api.CryptoOrder) tea.Cmd {
+	now := time.Now()
+	m.TrackedOrder = &OrderTracker{
+		OrderID:   order.ID,
+		Symbol:    order.Symbol,
+		Side:      order.Side,
+		Type:      order.Type,
+		Quantity:  order.Quantity,
+		State:     order.State,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+	return m.pollOrderCmd(order.ID)
+}
+
+// pollOrderCmd fetches the order after orderPollInterval. Polling is not
+// tied to the screen context so tracking continues across screens.
+func (m *AppModel) pollOrderCmd(orderID string) tea.Cmd {
+	client := m.CryptoClient
+	return tea.Tick(orderPollInterval, func(time.Time) tea.Msg {
+		if client == nil {
+			return orderProgressMsg{orderID: orderID, err: fmt.Errorf("not authenticated")}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		order, err := client.GetCryptoOrderContext(ctx, orderID)
+		if err != nil {
+			return orderProgressMsg{orderID: orderID, err: err}
+		}
+		if order.IsTerminal() {
+			return orderCompletedMsg{order: *order}
+		}
+		return orderProgressMsg{orderID: orderID, order: order}
+	})
+}
+
+// update applies a poll result to the tracked order
+func (t *OrderTracker) update(order api.CryptoOrder) {
+	t.State = order.State
+	t.Filled = order.FilledAssetQuantity
+	t.AveragePrice = order.AveragePrice
+	if order.Quantity.IsPositive() {
+		t.Quantity = order.Quantity
+	}
+	t.LastError = ""
+	t.UpdatedAt = time.Now()
+}
+
+// handleOrderProgress keeps polling until the order is done or tracking times out
+func (m *AppModel) handleOrderProgress(msg orderProgressMsg) tea.Cmd {
+	t := m.TrackedOrder
+	// Drop polls for an order that is no longer tracked
+	if t == nil || t.OrderID != msg.orderID {
+		return nil
+	}
+
+	if msg.err != nil {
+		if api.IsAuthError(msg.err) {
+			m.TrackedOrder = nil
+			m.promptForAPIKey(msg.err)
+			return nil
+		}
+		t.LastError = describeAPIError("Order status check failed", msg.err)
+	} else {
+		t.update(*msg.order)
+	}
+
+	if time.Since(t.StartedAt) > orderTrackTimeout {
+		m.Notice = fmt.Sprintf("⏳ %s %s order is still %s, see Order History for updates",
+			strings.ToUpper(t.Side), t.Symbol, strings.ReplaceAll(t.State, "_", " "))
+		m.TrackedOrder = nil
+		return nil
+	}
+
+	return m.pollOrderCmd(t.OrderID)
+}
+
+// handleOrderCompleted reports the final outcome and refreshes the portfolio
+func (m *AppModel) handleOrderCompleted(msg orderCompletedMsg) tea.Cmd {
+	t := m.TrackedOrder
+	if t == nil || t.OrderID != msg.order.ID {
+		return nil
+	}
+	t.update(msg.order)
+	m.TrackedOrder = nil
+
+	switch t.State {
+	case "filled":
+		m.Notice = fmt.Sprintf("✅ Order filled: %s %s %s @ %s",
+			strings.ToUpper(t.Side), t.Filled, t.Symbol, ui.FormatValue(t.AveragePrice))
+	case "canceled":
+		m.Notice = fmt.Sprintf("🚫 Order canceled: %s %s (%s of %s filled)",
+			strings.ToUpper(t.Side), t.Symbol, t.Filled, t.Quantity)
+	default:
+		m.Notice = fmt.Sprintf("❌ Order %s: %s %s", t.State, strings.ToUpper(t.Side), t.Symbol)
+	}
+
+	if m.Authenticated {
+		return m.loadCryptoPortfolioCmd()
+	}
+	return nil
+}
+
+// orderTrackerPanel renders the live status of the tracked order, or the
+// last completion notice
+func (m *AppModel) orderTrackerPanel() string {
+	t := m.TrackedOrder
+	if t == nil {
+		if m.Notice == "" {
+			return ""
+		}
+		return ui.InfoStyle.Render(m.Notice) + "\n"
+	}
+
+	var panel strings.Builder
+	panel.WriteString(fmt.Sprintf("📡 Tracking %s %s %s order: %s\n",
+		strings.ToUpper(t.Side), t.Symbol, strings.ToUpper(t.Type), strings.ReplaceAll(t.State, "_", " ")))
+
+	if t.Quantity.IsPositive() {
+		fraction := t.Filled.Div(t.Quantity).Float64()
+		if fraction > 1 {
+			fraction = 1
+		}
+		const width = 20
+		bar := strings.Repeat("█", int(fraction*width)) + strings.Repeat("░", width-int(fraction*width))
+		panel.WriteString(fmt.Sprintf("[%s] %3.0f%%  %s / %s", bar, fraction*100, t.Filled, t.Quantity))
+		if t.Filled.IsPositive() && t.AveragePrice.IsPositive() {
+			panel.WriteString(fmt.Sprintf(" @ avg %s", ui.FormatValue(t.AveragePrice)))
+		}
+		panel.WriteString("\n")
+	}
+
+	if t.LastError != "" {
+		panel.WriteString(ui.NegativeStyle.Render(t.LastError) + "\n")
+	}
+
+	return ui.InfoStyle.Render(panel.String()) + "\n"
+}
@@ -0,0 +1,419 @@
+package models
+
+import (
+	"crypto/ed25519"
+	"dazedtrader/api"
+	"dazedtrader/auth"
+	"dazedtrader/ui"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// What the passphrase screen is asked for
+const (
+	passphraseUnlock   = iota // unlock the stored API key
+	passphraseMigrate         // encrypt an API key stored before encryption
+	passphraseNew             // encrypt a newly verified API key before saving it
+	passphraseGenerate        // encrypt a newly generated private key
+	passphrasePending         // unlock a generated private key waiting for its API key
+)
+
+// storedKeyLifetime is how long a saved API key is kept before it has to be
+// entered again
+const storedKeyLifetime = 30 * 24 * time.Hour
+
+// PassphraseForm holds the passphrase screen. New passphrases are typed
+// twice; First holds the first entry while it is repeated.
+type PassphraseForm struct {
+	Mode    int
+	Input   string
+	First   string
+	Working bool
+
+	stored      *auth.APIKeyFile // key file to unlock or migrate
+	credentials string           // verified credentials to save
+	pending     *auth.PendingKey // generated key to unlock
+
+	// Key pair made for passphraseGenerate before the passphrase is submitted
+	publicKey  string
+	privateKey ed25519.PrivateKey
+}
+
+// passphraseDoneMsg is sent once a passphrase has been checked or the key
+// sealed with it; unlocked is set when that logged the user in
+type passphraseDoneMsg struct{ unlocked bool }
+
+// unlocking reports whether the passphrase opens something sealed earlier,
+// so it is typed only once
+func (f PassphraseForm) unlocking() bool {
+	return f.Mode == passphraseUnlock || f.Mode == passphrasePending
+}
+
+// confirming reports whether the passphrase being typed is the repeat of a
+// new one
+func (f PassphraseForm) confirming() bool {
+	return f.First != ""
+}
+
+// openPassphrase shows the passphrase screen for mode
+func (m *AppModel) openPassphrase(mode int, stored *auth.APIKeyFile, credentials string) {
+	m.Passphrase = PassphraseForm{Mode: mode, stored: stored, credentials: credentials}
+	m.State = StatePassphrase
+}
+
+// storedKeyLoadedMsg carries the active profile's key once its credential
+// store has been read; login is set when the login screen should be shown
+// if there is no key
+type storedKeyLoadedMsg struct {
+	stored *auth.APIKeyFile
+	err    error
+	login  bool
+}
+
+// loadStoredKeyCmd reads the active profile's key in the background, since a
+// credential command can take a while or prompt, e.g. for a GPG passphrase.
+// Commands are handed the terminal, suspending the UI until they exit.
+func (m *AppModel) loadStoredKeyCmd(login bool) tea.Cmd {
+	if m.credentials == nil {
+		return nil
+	}
+
+	m.LoadingKey = true
+	if store, ok := m.credentials.(auth.CommandStore); ok {
+		run, err := store.NewRun(os.Stderr)
+		if err != nil {
+			return func() tea.Msg {
+				return storedKeyLoadedMsg{err: err, login: login}
+			}
+		}
+		return tea.ExecProcess(run.Cmd, func(err error) tea.Msg {
+			stored, err := run.Result(err)
+			return storedKeyLoadedMsg{stored: stored, err: err, login: login}
+		})
+	}
+
+	store := m.credentials
+	return func() tea.Msg {
+		stored, err := store.Load()
+		return storedKeyLoadedMsg{stored: stored, err: err, login: login}
+	}
+}
+
+// handleStoredKeyLoaded opens the loaded key, falling back to the login
+// screen when asked to and there is none
+func (m *AppModel) handleStoredKeyLoaded(msg storedKeyLoadedMsg) tea.Cmd {
+	m.LoadingKey = false
+	if m.openStoredKey(msg.stored, msg.err) {
+		return m.startSession()
+	}
+	if msg.login {
+		m.APIKeyForm = APIKeyForm{}
+		m.State = StateLogin
+	}
+	return nil
+}
+
+// openStoredKey asks for the passphrase of a saved, unexpired API key, or
+// for a new one when the key was saved in plaintext. Keys managed outside
+// the app log in straight away. It reports whether there was such a key.
+func (m *AppModel) openStoredKey(stored *auth.APIKeyFile, err error) bool {
+	if err != nil {
+		m.Error = fmt.Sprintf("Failed to load API key for profile %s: %v", m.Profile, err)
+		return false
+	}
+	if stored == nil {
+		return false
+	}
+	if stored.ExpiresAt != 0 && time.Now().Unix() >= stored.ExpiresAt {
+		// API key expired, clear it
+		if !m.credentials.ReadOnly() {
+			m.credentials.Clear()
+		}
+		return false
+	}
+
+	switch {
+	case stored.Encrypted():
+		m.openPassphrase(passphraseUnlock, stored, "")
+	case m.credentials.ReadOnly():
+		m.loginWithStoredKey(stored.APIKey, stored.Username)
+	default:
+		m.openPassphrase(passphraseMigrate, stored, "")
+	}
+	return true
+}
+
+// SubmitPassphrase unlocks or seals the API key with the entered
+// passphrase, logging in with a stored key once it is unlocked
+func (m *AppModel) SubmitPassphrase() (bool, error) {
+	form := &m.Passphrase
+	passphrase := []byte(form.Input)
+
+	defer func() {
+		form.Working = false
+	}()
+
+	switch form.Mode {
+	case passphraseUnlock:
+		data, err := form.stored.Unlock(passphrase)
+		if errors.Is(err, auth.ErrWrongPassphrase) {
+			form.Input = ""
+			m.Error = "Wrong passphrase, please try again"
+			return false, nil
+		}
+		if err != nil {
+			m.Error = fmt.Sprintf("Failed to unlock API key: %v", err)
+			return false, err
+		}
+		return m.loginWithStoredKey(data.APIKey, data.Username), nil
+
+	case passphraseMigrate:
+		// Encrypting is best effort; the key was usable in plaintext before
+		stored := form.stored
+		if err := m.credentials.Save(stored.APIKey, stored.Username, stored.ExpiresAt, passphrase); err != nil {
+			m.Error = fmt.Sprintf("Failed to encrypt stored API key, it stays unencrypted: %v", err)
+		}
+		return m.loginWithStoredKey(stored.APIKey, stored.Username), nil
+
+	case passphraseNew:
+		expiresAt := time.Now().Add(storedKeyLifetime).Unix()
+		if err := m.credentials.Save(form.credentials, m.Username, expiresAt, passphrase); err != nil {
+			m.Error = fmt.Sprintf("Failed to save API key: %v", err)
+			return false, err
+		}
+		m.Passphrase = PassphraseForm{}
+		m.State = StateMenu
+
+	case passphraseGenerate:
+		if err := auth.SavePendingKey(m.Profile, form.privateKey, passphrase); err != nil {
+			m.Error = fmt.Sprintf("Failed to save generated key: %v", err)
+			return false, err
+		}
+		m.showKeyGen(form.publicKey, form.privateKey, passphrase)
+
+	case passphrasePending:
+		privateKey, err := form.pending.Unlock(passphrase)
+		if errors.Is(err, auth.ErrWrongPassphrase) {
+			form.Input = ""
+			m.Error = "Wrong passphrase, please try again"
+			return false, nil
+		}
+		if err != nil {
+			m.Error = fmt.Sprintf("Failed to unlock generated key: %v", err)
+			return false, err
+		}
+		m.showKeyGen(form.pending.PublicKey, privateKey, passphrase)
+	}
+
+	return false, nil
+}
+
+// loginWithStoredKey logs in with credentials read from the API key file
+func (m *AppModel) loginWithStoredKey(credentials, username string) bool {
+	m.CryptoClient = m.newCryptoClient(credentials)
+	if m.CryptoClient == nil {
+		m.Error = "Stored API key is invalid, please set it up again"
+		if !m.credentials.ReadOnly() {
+			m.credentials.Clear()
+		}
+		m.Passphrase = PassphraseForm{}
+		m.State = StateLogin
+		return false
+	}
+
+	m.Authenticated = true
+	m.Username = username
+	m.Passphrase = PassphraseForm{}
+	m.State = StateMenu
+	return true
+}
+
+// submitPassphraseCmd submits the passphrase in the background. Working is
+// set right away so keys, a repeated Enter included, are ignored until
+// SubmitPassphrase has finished.
+func (m *AppModel) submitPassphraseCmd() tea.Cmd {
+	// The key pair is made here, once, so the public key shown is always
+	// the one whose private key is saved
+	if form := &m.Passphrase; form.Mode == passphraseGenerate && form.privateKey == nil {
+		publicKey, privateKey, err := api.GenerateKey()
+		if err != nil {
+			m.Error = err.Error()
+			return nil
+		}
+		form.publicKey, form.privateKey = publicKey, privateKey
+	}
+
+	m.Passphrase.Working = true
+	return func() tea.Msg {
+		unlocked, _ := m.SubmitPassphrase()
+		return passphraseDoneMsg{unlocked: unlocked}
+	}
+}
+
+// handlePassphraseKeys takes all keys on the passphrase screen so any
+// character can be part of the passphrase
+func (m *AppModel) handlePassphraseKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := &m.Passphrase
+	if form.Working {
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		if form.Input == "" {
+			return m, nil
+		}
+		m.Error = ""
+		if form.unlocking() {
+			return m, m.submitPassphraseCmd()
+		}
+		// New passphrases are typed twice
+		if !form.confirming() {
+			form.First, form.Input = form.Input, ""
+			return m, nil
+		}
+		if form.Input != form.First {
+			form.First, form.Input = "", ""
+			m.Error = "Passphrases don't match, please choose one again"
+			return m, nil
+		}
+		return m, m.submitPassphraseCmd()
+
+	case tea.KeyEsc:
+		m.Error = ""
+		switch form.Mode {
+		case passphraseUnlock:
+			// Stay logged out; the key can be unlocked from the menu later
+			m.Passphrase = PassphraseForm{}
+			m.State = StateMenu
+		case passphraseMigrate:
+			// Use the key as it is this time and ask again on the next start
+			m.loginWithStoredKey(form.stored.APIKey, form.stored.Username)
+			return m, m.startSession()
+		case passphraseNew:
+			// Keep the key for this session only
+			m.Passphrase = PassphraseForm{}
+			m.State = StateMenu
+		case passphraseGenerate, passphrasePending:
+			// A generated key stays saved for later
+			m.Passphrase = PassphraseForm{}
+			m.State = StateLogin
+		}
+
+	case tea.KeyTab:
+		// A forgotten passphrase can only be replaced by a new API key, or
+		// by a new key pair for a generated key
+		switch form.Mode {
+		case passphraseUnlock:
+			m.Error = ""
+			m.Passphrase = PassphraseForm{}
+			m.APIKeyForm = APIKeyForm{}
+			m.State = StateLogin
+		case passphrasePending:
+			m.Error = ""
+			m.openPassphrase(passphraseGenerate, nil, "")
+		}
+
+	case tea.KeyBackspace:
+		if runes := []rune(form.Input); len(runes) > 0 {
+			form.Input = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeyCtrlA:
+		form.Input = ""
+
+	case tea.KeyRunes, tea.KeySpace:
+		form.Input += string(msg.Runes)
+	}
+
+	return m, nil
+}
+
+// startSession loads what the screens need after logging in with a stored
+// key, like Init does when the app starts logged in
+func (m *AppModel) startSession() tea.Cmd {
+	if !m.Authenticated {
+		return nil
+	}
+	return tea.Batch(
+		m.loadCryptoPortfolioCmd(),
+		m.loadTradingPairsCmd(),
+		m.startTicking(),
+	)
+}
+
+// passphraseView renders the passphrase screen
+func (m *AppModel) passphraseView() string {
+	form := m.Passphrase
+
+	var title string
+	switch form.Mode {
+	case passphraseUnlock:
+		title = ui.HeaderStyle.Render("🔒 UNLOCK API KEY")
+	case passphraseGenerate, passphrasePending:
+		title = ui.HeaderStyle.Render("🔑 GENERATE KEY PAIR")
+	default:
+		title = ui.HeaderStyle.Render("🔒 ENCRYPT API KEY")
+	}
+
+	var content strings.Builder
+
+	if m.Error != "" {
+		content.WriteString(ui.NegativeStyle.Render("❌ " + m.Error + "\n\n"))
+	}
+
+	switch form.Mode {
+	case passphraseUnlock:
+		content.WriteString(ui.PositiveStyle.Render("Enter the passphrase for your stored API key:") + "\n\n")
+	case passphraseMigrate:
+		content.WriteString("Your API key is stored unencrypted from an earlier version.\n")
+		content.WriteString(ui.PositiveStyle.Render("Choose a passphrase to encrypt it:") + "\n\n")
+	case passphraseNew:
+		content.WriteString("Your API key works. It is saved encrypted so it can't be read from disk.\n")
+		content.WriteString(ui.PositiveStyle.Render("Choose a passphrase to encrypt it:") + "\n\n")
+	case passphraseGenerate:
+		content.WriteString("A new Ed25519 key pair is generated for you to create the API key with.\n")
+		content.WriteString("Its private key never leaves this computer and is saved encrypted.\n")
+		content.WriteString(ui.PositiveStyle.Render("Choose a passphrase to encrypt it:") + "\n\n")
+	case passphrasePending:
+		content.WriteString("A key pair generated earlier is waiting for its API key.\n")
+		content.WriteString(ui.PositiveStyle.Render("Enter the passphrase you chose for it:") + "\n\n")
+	}
+	if form.confirming() {
+		content.WriteString("Repeat the passphrase:\n")
+	}
+
+	if form.Working {
+		if form.unlocking() {
+			content.WriteString(ui.LoadingStyle.Render("🔄 Unlocking...") + "\n")
+		} else if form.Mode == passphraseGenerate {
+			content.WriteString(ui.LoadingStyle.Render("🔄 Generating key pair...") + "\n")
+		} else {
+			content.WriteString(ui.LoadingStyle.Render("🔄 Encrypting API key...") + "\n")
+		}
+	} else {
+		content.WriteString(ui.InputStyle.Render(strings.Repeat("*", len([]rune(form.Input)))+"│") + "\n\n")
+		content.WriteString("Ctrl+A to clear all text\n")
+	}
+
+	var footer string
+	switch form.Mode {
+	case passphraseUnlock:
+		footer = ui.InfoStyle.Render("Enter to unlock • Tab to enter a new API key if you forgot the passphrase • 'Esc' to continue logged out")
+	case passphraseMigrate:
+		footer = ui.InfoStyle.Render("Enter to continue • 'Esc' to skip for now, you'll be asked again next time")
+	case passphraseNew:
+		footer = ui.InfoStyle.Render("Enter to continue • 'Esc' to use the key for this session without saving it")
+	case passphraseGenerate:
+		footer = ui.InfoStyle.Render("Enter to continue • 'Esc' to go back")
+	case passphrasePending:
+		footer = ui.InfoStyle.Render("Enter to unlock • Tab to start over with a new key pair if you forgot the passphrase • 'Esc' to go back")
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
+}
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"dazedtrader/auth"
+	"dazedtrader/ui"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ProfilePicker holds the profile picker. While Naming, keys go to the name
+// of a new profile.
+type ProfilePicker struct {
+	Names  []string
+	Cursor int
+	Naming bool
+	Name   string
+}
+
+// openProfilePicker lists the stored profiles with the active one selected
+func (m *AppModel) openProfilePicker() {
+	if m.ephemeralCredentials {
+		m.Error = "Profiles aren't used with credentials given on the command line"
+		return
+	}
+	if m.LoadingKey {
+		return
+	}
+
+	names, err := m.settings.Profiles()
+	if err != nil {
+		m.Error = fmt.Sprintf("Failed to list profiles: %v", err)
+	}
+
+	// The active profile is listed even before it has a stored key
+	active := -1
+	for i, name := range names {
+		if name == m.Profile {
+			active = i
+		}
+	}
+	if active < 0 {
+		names = append(names, m.Profile)
+		active = len(names) - 1
+	}
+
+	m.ProfilePicker = ProfilePicker{Names: names, Cursor: active}
+	m.State = StateProfiles
+}
+
+// switchProfile logs out of the active profile and into name, asking for the
+// passphrase of its stored key or for a new key when it has none
+func (m *AppModel) switchProfile(name string) tea.Cmd {
+	m.ProfilePicker = ProfilePicker{}
+	m.State = StateMenu
+	if name == m.Profile {
+		return nil
+	}
+
+	m.CancelRequests()
+	m.endSession()
+	if err := m.useProfile(name); err != nil {
+		return nil
+	}
+	return m.loadStoredKeyCmd(true)
+}
+
+// useProfile makes name the active profile with its configured credential
+// store
+func (m *AppModel) useProfile(name string) error {
+	store, err := m.settings.CredentialStore(name)
+	if err != nil {
+		m.Error = err.Error()
+		return err
+	}
+	m.Profile = name
+	m.credentials = store
+	return nil
+}
+
+// handleProfileKeys takes all keys on the profile picker so new profile
+// names can be typed freely
+func (m *AppModel) handleProfileKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	picker := &m.ProfilePicker
+
+	if picker.Naming {
+		switch msg.String() {
+		case "esc":
+			picker.Naming = false
+			m.Error = ""
+		case "enter":
+			if err := auth.ValidateProfileName(picker.Name); err != nil {
+				m.Error = err.Error()
+				return m, nil
+			}
+			m.Error = ""
+			return m, m.switchProfile(picker.Name)
+		case "backspace":
+			if len(picker.Name) > 0 {
+				picker.Name = picker.Name[:len(picker.Name)-1]
+			}
+		default:
+			if len(msg.String()) == 1 {
+				char := msg.String()[0]
+				if (char >= 'A' && char <= 'Z') || (char >= 'a' && char <= 'z') ||
+					(char >= '0' && char <= '9') || char == '-' || char == '_' {
+					picker.Name += string(char)
+				}
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.ProfilePicker = ProfilePicker{}
+		m.State = StateMenu
+		m.Error = ""
+	case "up", "k":
+		if picker.Cursor > 0 {
+			picker.Cursor--
+		}
+	case "down", "j":
+		if picker.Cursor < len(picker.Names)-1 {
+			picker.Cursor++
+		}
+	case "n":
+		picker.Naming = true
+		picker.Name = ""
+		m.Error = ""
+	case "enter":
+		if picker.Cursor < len(picker.Names) {
+			m.Error = ""
+			return m, m.switchProfile(picker.Names[picker.Cursor])
+		}
+	}
+	return m, nil
+}
+
+// profileStatus names the active profile; it heads every screen so keys of
+// different accounts can't be mixed up
+func (m *AppModel) profileStatus() string {
+	if m.ephemeralCredentials {
+		return ui.ProfileStyle.Render("👤 Profile: none (credentials from the command line, not saved)") + "\n"
+	}
+	return ui.ProfileStyle.Render("👤 Profile: "+m.Profile) + "\n"
+}
+
+// profilesView renders the profile picker
+func (m *AppModel) profilesView() string {
+	title := ui.HeaderStyle.Render("👤 PROFILES")
+	picker := m.ProfilePicker
+
+	var content strings.Builder
+
+	if m.Error != "" {
+		content.WriteString(ui.NegativeStyle.Render("❌ " + m.Error + "\n\n"))
+	}
+
+	content.WriteString("Each profile keeps its own API key, unlocked with its own passphrase.\n\n")
+
+	for i, name := range picker.Names {
+		cursor := "  "
+		if i == picker.Cursor && !picker.Naming {
+			cursor = "► "
+		}
+		line := cursor + name
+		if name == m.Profile {
+			line = ui.PositiveStyle.Render(line + "  (active)")
+		}
+		content.WriteString(line + "\n")
+	}
+
+	footer := ui.InfoStyle.Render("↑↓ select • Enter to switch • 'N' for a new profile • 'Esc' to go back")
+	if picker.Naming {
+		content.WriteString("\nNew profile name (letters, digits, '-' and '_'):\n")
+		content.WriteString(ui.InputStyle.Render(picker.Name+"│") + "\n")
+		footer = ui.InfoStyle.Render("Enter to create and switch to it • 'Esc' to cancel")
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
+}
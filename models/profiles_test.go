@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestSwitchProfileEndsTickLoop(t *testing.T) {
+	m := &AppModel{State: StateMenu, Profile: "default", Authenticated: true}
+	m.startTicking()
+	ticks := []tickMsg{{loop: m.tickLoop}}
+
+	for _, name := range []string{"work", "default"} {
+		previous := ticks[len(ticks)-1]
+		m.switchProfile(name)
+		if _, cmd := m.Update(previous); cmd != nil {
+			t.Errorf("tick loop of the previous profile still running after switching to %s", name)
+		}
+
+		// The profile's key unlocks and starts a session of its own
+		m.Authenticated = true
+		m.startSession()
+		ticks = append(ticks, tickMsg{loop: m.tickLoop})
+	}
+
+	live := 0
+	for _, tick := range ticks {
+		if _, cmd := m.Update(tick); cmd != nil {
+			live++
+		}
+	}
+	if live != 1 {
+		t.Errorf("%d tick loops running, want 1", live)
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"dazedtrader/api"
+	"dazedtrader/decimal"
 	"dazedtrader/ui"
 	"fmt"
 	"strings"
@@ -37,10 +39,11 @@ func (m *AppModel) LoginView() string {
 
 		content.WriteString(ui.InputStyle.Render(apiKeyInput) + "\n\n")
 		content.WriteString("Press Ctrl+V to paste, Tab to toggle visibility, Enter to verify, Esc to cancel\n")
-		content.WriteString("Ctrl+A to clear all text\n")
+		content.WriteString("Ctrl+A to clear all text\n\n")
+		content.WriteString("No key pair yet? Ctrl+G generates one to create the API key with\n")
 	}
 
-	footer := ui.InfoStyle.Render("Tip: Your API key is stored securely locally and used for Robinhood Crypto API access")
+	footer := ui.InfoStyle.Render("Tip: Your API key is stored locally, encrypted with a passphrase, and used for Robinhood Crypto API access")
 
 	return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
 }
@@ -59,6 +62,9 @@ func (m *AppModel) DashboardView() string {
 		content.WriteString(ui.NegativeStyle.Render("❌ " + m.Error + "\n\n"))
 	}
 
+	content.WriteString(m.orderTrackerPanel())
+	content.WriteString(m.orderIntentsPanel())
+
 	// Show data source indicator
 	if m.DataSource != "" {
 		var sourceIcon string
@@ -69,6 +75,7 @@ func (m *AppModel) DashboardView() string {
 		}
 		content.WriteString(ui.InfoStyle.Render(sourceIcon + " Data: " + m.DataSource + "\n\n"))
 	}
+	content.WriteString(m.accountStatus())
 
 	if m.Loading {
 		content.WriteString(ui.LoadingStyle.Render("🔄 Loading portfolio data...\n\n"))
@@ -80,19 +87,19 @@ func (m *AppModel) DashboardView() string {
 		content.WriteString("📈 PORTFOLIO SUMMARY\n")
 		content.WriteString("═════════════════════\n")
 		// Calculate total value from holdings
-		totalValue := 0.0
+		totalValue := decimal.Zero
 		for _, holding := range m.Portfolio.Holdings {
-			totalValue += holding.MarketValue
+			totalValue = totalValue.Add(holding.MarketValue)
 		}
 		content.WriteString(fmt.Sprintf("Total Value:     %s\n", ui.FormatMarketValue(totalValue)))
 		// Calculate total day change from holdings
-		totalDayChange := 0.0
+		totalDayChange := decimal.Zero
 		for _, holding := range m.Portfolio.Holdings {
-			totalDayChange += holding.DayChange
+			totalDayChange = totalDayChange.Add(holding.DayChange)
 		}
 		dayChangePct := 0.0
-		if totalValue > 0 {
-			dayChangePct = (totalDayChange / (totalValue - totalDayChange)) * 100
+		if totalValue.IsPositive() {
+			dayChangePct = totalDayChange.Div(totalValue.Sub(totalDayChange)).Float64() * 100
 		}
 		content.WriteString(fmt.Sprintf("Day Change:      %s (%s)\n",
 			ui.FormatCurrency(totalDayChange),
@@ -109,13 +116,13 @@ func (m *AppModel) DashboardView() string {
 			for _, pos := range m.Portfolio.Holdings {
 				// Show all holdings, even with 0 quantity
 				priceStr := "Loading..."
-				if pos.CurrentPrice > 0 {
+				if pos.CurrentPrice.IsPositive() {
 					priceStr = ui.FormatPrice(pos.CurrentPrice)
 				}
 
-				content.WriteString(fmt.Sprintf("%-10s    %12.4f    %-15s    %-15s    %s\n",
+				content.WriteString(fmt.Sprintf("%-10s    %12s    %-15s    %-15s    %s\n",
 					pos.AssetCode,
-					pos.Quantity,
+					pos.Quantity.StringFixed(4),
 					priceStr,
 					ui.FormatMarketValue(pos.MarketValue),
 					ui.FormatCurrency(pos.DayChange),
@@ -142,13 +149,13 @@ func (m *AppModel) DashboardView() string {
 			for i := 0; i < maxOrders; i++ {
 				order := m.Portfolio.Orders[i]
 				avgPriceStr := "Market"
-				if order.AveragePrice > 0 {
+				if order.AveragePrice.IsPositive() {
 					avgPriceStr = ui.FormatValue(order.AveragePrice)
 				}
-				content.WriteString(fmt.Sprintf("%-8s  %-4s  %8.4f  %-10s  %s\n",
+				content.WriteString(fmt.Sprintf("%-8s  %-4s  %8s  %-10s  %s\n",
 					order.Symbol,
 					strings.ToUpper(order.Side),
-					order.FilledQuantity,
+					order.FilledQuantity.StringFixed(4),
 					avgPriceStr,
 					order.State,
 				))
@@ -161,6 +168,9 @@ func (m *AppModel) DashboardView() string {
 			content.WriteString(fmt.Sprintf("Last updated: %s\n",
 				m.Portfolio.LastUpdated.Format("3:04 PM")))
 		}
+		content.WriteString(m.apiBudgetStatus())
+		content.WriteString(m.clockSkewStatus())
+		content.WriteString(m.schemaDriftStatus())
 	}
 
 	footer := ui.InfoStyle.Render("Press 'R' or 'F5' to refresh • 'Esc' to return to menu • Auto-refresh every 5s")
@@ -227,6 +237,9 @@ func (m *AppModel) marketDataView() string {
 			content.WriteString(fmt.Sprintf("Last updated: %s\n",
 				m.MarketData.LastUpdated.Format("3:04 PM")))
 		}
+		content.WriteString(m.apiBudgetStatus())
+		content.WriteString(m.clockSkewStatus())
+		content.WriteString(m.schemaDriftStatus())
 
 		content.WriteString("\n")
 		content.WriteString(ui.InfoStyle.Render("💡 All cryptocurrencies shown are available for trading on Robinhood"))
@@ -253,22 +266,51 @@ func (m *AppModel) orderHistoryView() string {
 		content.WriteString(ui.NegativeStyle.Render("❌ " + m.Error + "\n\n"))
 	}
 
-	if m.Loading {
+	content.WriteString(m.orderTrackerPanel())
+	content.WriteString(m.orderIntentsPanel())
+	content.WriteString(m.accountStatus())
+
+	filtered := m.orderFilterActive()
+	if m.EditingFilter {
+		content.WriteString(m.orderFilterView())
+	}
+
+	// Recent orders from the portfolio refresh followed by any older pages,
+	// or the server-side filter results
+	allOrders := m.visibleOrders()
+	pages := m.OrderPages
+	if filtered {
+		pages = m.FilterPages
+		content.WriteString(fmt.Sprintf("🔍 Filter: %s\n\n", m.FilterForm.summary()))
+	}
+
+	if filtered && len(allOrders) == 0 {
+		if m.LoadingOrders {
+			content.WriteString(ui.LoadingStyle.Render("🔄 Loading matching orders...\n\n"))
+		} else {
+			content.WriteString("📊 No orders match the filter.\n")
+			content.WriteString("Press 'F' to change it or 'C' to clear it.\n\n")
+		}
+	} else if m.Loading && !filtered {
 		content.WriteString(ui.LoadingStyle.Render("🔄 Loading order history...\n\n"))
-	} else if m.Portfolio == nil {
+	} else if m.Portfolio == nil && !filtered {
 		content.WriteString("📊 No portfolio data available.\n")
 		content.WriteString("Press 'R' or 'F5' to refresh.\n\n")
-	} else if len(m.Portfolio.Orders) == 0 {
+	} else if len(allOrders) == 0 {
 		content.WriteString("📊 No orders found.\n")
 		content.WriteString("Your order history will appear here once you start trading.\n\n")
 	} else {
 		// Order History
-		content.WriteString("📋 RECENT ORDERS\n")
+		if filtered {
+			content.WriteString("📋 MATCHING ORDERS\n")
+		} else {
+			content.WriteString("📋 RECENT ORDERS\n")
+		}
 		content.WriteString("═══════════════════\n")
-		content.WriteString("Date/Time         Symbol      Side   Type    Quantity      Avg Price    State\n")
-		content.WriteString("─────────────────────────────────────────────────────────────────────────────\n")
+		content.WriteString("  Date/Time         Symbol      Side   Type    Quantity      Avg Price    State\n")
+		content.WriteString("───────────────────────────────────────────────────────────────────────────────\n")
 
-		for _, order := range m.Portfolio.Orders {
+		for i, order := range allOrders {
 			// Parse and format the timestamp
 			createdTime := order.CreatedAt
 			if len(createdTime) > 16 {
@@ -276,7 +318,7 @@ func (m *AppModel) orderHistoryView() string {
 			}
 
 			avgPriceStr := "Market"
-			if order.AveragePrice > 0 {
+			if order.AveragePrice.IsPositive() {
 				avgPriceStr = ui.FormatValue(order.AveragePrice)
 			}
 
@@ -299,28 +341,49 @@ func (m *AppModel) orderHistoryView() string {
 				stateStr = ui.LoadingStyle.Render(order.State)
 			}
 
-			content.WriteString(fmt.Sprintf("%-16s  %-10s  %-5s  %-6s  %8.4f      %-12s %s\n",
+			cursor := "  "
+			if i == m.OrderCursor {
+				cursor = ui.SelectedStyle.Render("►") + " "
+			}
+
+			content.WriteString(fmt.Sprintf("%s%-16s  %-10s  %-5s  %-6s  %8s      %-12s %s\n",
+				cursor,
 				createdTime,
 				order.Symbol,
 				sideStr,
 				strings.ToUpper(order.Type),
-				order.FilledQuantity,
+				order.FilledQuantity.StringFixed(4),
 				avgPriceStr,
 				stateStr,
 			))
 		}
 
 		content.WriteString("\n")
-		content.WriteString(fmt.Sprintf("Showing %d most recent orders\n", len(m.Portfolio.Orders)))
+		if filtered {
+			content.WriteString(fmt.Sprintf("Showing %d matching orders\n", len(allOrders)))
+		} else {
+			content.WriteString(fmt.Sprintf("Showing %d most recent orders\n", len(allOrders)))
+		}
+		if m.LoadingOrders {
+			content.WriteString(ui.LoadingStyle.Render("🔄 Loading older orders...") + "\n")
+		} else if pages != nil && pages.HasNext() {
+			content.WriteString("Press 'M' to load older orders\n")
+		}
 
 		// Last updated
-		if !m.Portfolio.LastUpdated.IsZero() {
+		if m.Portfolio != nil && !m.Portfolio.LastUpdated.IsZero() {
 			content.WriteString(fmt.Sprintf("Last updated: %s\n",
 				m.Portfolio.LastUpdated.Format("3:04 PM")))
 		}
+		content.WriteString(m.apiBudgetStatus())
+		content.WriteString(m.clockSkewStatus())
+		content.WriteString(m.schemaDriftStatus())
 	}
 
-	footer := ui.InfoStyle.Render("Press 'R' or 'F5' to refresh • 'Esc' to return to menu")
+	footer := ui.InfoStyle.Render("↑↓ select • Enter for fills • 'R' or 'F5' to refresh • 'M' to load older orders • 'F' to filter • 'X' to cancel open orders • 'Esc' to return to menu")
+	if filtered {
+		footer = ui.InfoStyle.Render("↑↓ select • Enter for fills • 'R' or 'F5' to refresh • 'M' to load older orders • 'F' to filter • 'C' to clear filter • 'X' to cancel open orders • 'Esc' to return to menu")
+	}
 
 	return fmt.Sprintf("%s\n%s\n%s", title, ui.MenuStyle.Render(content.String()), footer)
 }
@@ -412,6 +475,57 @@ func (m *AppModel) formatNewsArticle(article NewsArticle, index int) string {
 	return result.String()
 }
 
+// apiBudgetStatus renders how many Robinhood requests can be sent before throttling
+func (m *AppModel) apiBudgetStatus() string {
+	if m.CryptoClient == nil || m.CryptoClient.Limiter == nil {
+		return ""
+	}
+
+	remaining := m.CryptoClient.RateLimitRemaining()
+	line := fmt.Sprintf("API budget: %d/%d requests", remaining, m.CryptoClient.Limiter.Capacity())
+	if remaining < refreshBudgetReserve {
+		return ui.NegativeStyle.Render(line+" (auto-refresh paused)") + "\n"
+	}
+	return line + "\n"
+}
+
+// clockSkewStatus warns when the local clock is further from Robinhood's than
+// the API tolerates. Requests are still signed with the corrected time.
+func (m *AppModel) clockSkewStatus() string {
+	if m.CryptoClient == nil {
+		return ""
+	}
+
+	skew, ok := m.CryptoClient.ClockSkew()
+	if !ok || (skew <= api.MaxClockSkew && skew >= -api.MaxClockSkew) {
+		return ""
+	}
+
+	direction := "behind"
+	if skew < 0 {
+		direction = "ahead of"
+		skew = -skew
+	}
+	return ui.NegativeStyle.Render(fmt.Sprintf("⚠ System clock is %s %s Robinhood's; requests are corrected, but please sync your clock", skew, direction)) + "\n"
+}
+
+// schemaDriftStatus warns when Robinhood responses no longer have the shape
+// the client expects, since affected values may show as zero
+func (m *AppModel) schemaDriftStatus() string {
+	if m.CryptoClient == nil {
+		return ""
+	}
+
+	drift := m.CryptoClient.SchemaDrift()
+	if len(drift) == 0 {
+		return ""
+	}
+
+	latest := drift[len(drift)-1]
+	return ui.NegativeStyle.Render(fmt.Sprintf("⚠ Robinhood's response format changed (%d fields, latest: %s %s: %s); run with --log for details",
+		len(drift), latest.Payload, latest.Field, latest.Problem)) + "\n"
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
@@ -501,9 +615,9 @@ func (m *AppModel) getTokenPriceIndicator(symbol string) string {
 	if m.Portfolio != nil {
 		for _, pos := range m.Portfolio.Holdings {
 			if pos.AssetCode == symbol || pos.AssetCode+"-USD" == symbol {
-				if pos.DayChange > 0 {
+				if pos.DayChange.IsPositive() {
 					return ui.PositiveStyle.Render(fmt.Sprintf("%s ↗", symbol))
-				} else if pos.DayChange < 0 {
+				} else if pos.DayChange.IsNegative() {
 					return ui.NegativeStyle.Render(fmt.Sprintf("%s ↘", symbol))
 				} else {
 					return ui.NeutralStyle.Render(fmt.Sprintf("%s →", symbol))
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"dazedtrader/decimal"
 	"fmt"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -41,6 +42,11 @@ var (
 		BorderTop(true).
 		BorderForeground(lipgloss.Color("#874BFD"))
 
+	// ProfileStyle names the active profile at the top of every screen
+	ProfileStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#EE6FF8"))
+
 	// Data display styles
 	ValueStyle = lipgloss.NewStyle().
 		Bold(true).
@@ -87,20 +93,23 @@ var (
 		Bold(true)
 )
 
-func FormatCurrency(value float64) string {
-	if value >= 0 {
-		return PositiveStyle.Render(fmt.Sprintf("+$%.2f", value))
+// Thresholds used to pick how many decimals a dollar amount is shown with
+var (
+	one      = decimal.NewFromInt(1)
+	ten      = decimal.NewFromInt(10)
+	thousand = decimal.NewFromInt(1000)
+	million  = decimal.NewFromInt(1000000)
+)
+
+func FormatCurrency(value decimal.Decimal) string {
+	if !value.IsNegative() {
+		return PositiveStyle.Render("+$" + value.StringFixed(2))
 	}
-	return NegativeStyle.Render(fmt.Sprintf("-$%.2f", -value))
+	return NegativeStyle.Render("-$" + value.Neg().StringFixed(2))
 }
 
-func FormatValue(value float64) string {
-	if value < 1.0 {
-		return ValueStyle.Render(fmt.Sprintf("$%.8f", value))
-	} else if value < 10.0 {
-		return ValueStyle.Render(fmt.Sprintf("$%.4f", value))
-	}
-	return ValueStyle.Render(fmt.Sprintf("$%.2f", value))
+func FormatValue(value decimal.Decimal) string {
+	return ValueStyle.Render("$" + value.StringFixed(dollarDecimals(value)))
 }
 
 func FormatPercentage(value float64) string {
@@ -123,20 +132,26 @@ func FormatCompact(value float64) string {
 	return ValueStyle.Render(fmt.Sprintf("$%.0f", value))
 }
 
-func FormatPrice(value float64) string {
-	if value < 1.0 {
-		return PriceStyle.Render(fmt.Sprintf("$%.8f", value))
-	} else if value < 10.0 {
-		return PriceStyle.Render(fmt.Sprintf("$%.4f", value))
+func FormatPrice(value decimal.Decimal) string {
+	return PriceStyle.Render("$" + value.StringFixed(dollarDecimals(value)))
+}
+
+func FormatMarketValue(value decimal.Decimal) string {
+	if !value.LessThan(million) {
+		return MarketValueStyle.Render("$" + value.Div(million).StringFixed(2) + "M")
+	} else if !value.LessThan(thousand) {
+		return MarketValueStyle.Render("$" + value.Div(thousand).StringFixed(2) + "K")
 	}
-	return PriceStyle.Render(fmt.Sprintf("$%.2f", value))
+	return MarketValueStyle.Render("$" + value.StringFixed(2))
 }
 
-func FormatMarketValue(value float64) string {
-	if value >= 1e6 {
-		return MarketValueStyle.Render(fmt.Sprintf("$%.2fM", value/1e6))
-	} else if value >= 1e3 {
-		return MarketValueStyle.Render(fmt.Sprintf("$%.2fK", value/1e3))
+// dollarDecimals shows sub-dollar prices with 8 decimals, single-digit
+// prices with 4 and everything else in cents
+func dollarDecimals(value decimal.Decimal) int {
+	if value.LessThan(one) {
+		return 8
+	} else if value.LessThan(ten) {
+		return 4
 	}
-	return MarketValueStyle.Render(fmt.Sprintf("$%.2f", value))
-}
\ No newline at end of file
+	return 2
+}